@@ -5,27 +5,46 @@ import (
 	"strings"
 	"syscall"
 
+	"github.com/openware/binance-cli/pkg/helpers"
+	"github.com/openware/binance-cli/pkg/mapping"
+	"github.com/openware/binance-cli/pkg/policy"
 	"github.com/openware/pkg/ika"
 	"golang.org/x/crypto/ssh/terminal"
 )
 
+// ConfigPath is an optional YAML or JSON file with the policies and mappings, environment variables take precedence
+var ConfigPath = ""
+
 type Config struct {
-	PlatformBaseUrl  string `env:"OPENDAX_BASE_URL"`
-	OpendaxApiKey    string `env:"OPENDAX_API_KEY"`
-	OpendaxApiSecret string `env:"OPENDAX_API_SECRET"`
-	BinanceApiKey    string `env:"BINANCE_API_KEY"`
-	BinanceSecret    string `env:"BINANCE_SECRET"`
+	PlatformBaseUrl   string `env:"OPENDAX_BASE_URL"`
+	OpendaxApiKey     string `env:"OPENDAX_API_KEY"`
+	OpendaxApiSecret  string `env:"OPENDAX_API_SECRET"`
+	BinanceApiKey     string `env:"BINANCE_API_KEY"`
+	BinanceSecret     string `env:"BINANCE_SECRET"`
+	EngineId          int64  `env:"OPENDAX_ENGINE_ID"`
+	BinanceRecvWindow int64  `env:"BINANCE_RECV_WINDOW"`
+
+	Retries      *helpers.RetryConfig `yaml:"retries" json:"retries"`
+	Fees         policy.FeePolicies   `yaml:"fees" json:"fees"`
+	MarketPolicy policy.MarketPolicy  `yaml:"markets" json:"markets"`
+	Currencies   mapping.Currencies   `yaml:"currencies" json:"currencies"`
+	Markets      mapping.Markets      `yaml:"market_symbols" json:"market_symbols"`
 }
 
 func readConfig() *Config {
 	config := &Config{}
-	err := ika.ReadConfig("", config)
+	err := ika.ReadConfig(ConfigPath, config)
 	if err != nil {
 		panic(err)
 	}
 	return config
 }
 
+// retryPolicy returns the retry policy of the HTTP clients, the default one with the configured fields overridden
+func (c *Config) retryPolicy() helpers.RetryPolicy {
+	return c.Retries.Policy()
+}
+
 func fetchBinanceKey(config *Config) {
 	if config.BinanceApiKey == "" {
 		fmt.Print("Enter BINANCE_API_KEY: ")
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/openware/binance-cli/pkg/helpers"
+	"github.com/openware/pkg/ika"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigRetryPolicy(t *testing.T) {
+	assert.Equal(t, helpers.DefaultRetryPolicy, (&Config{}).retryPolicy())
+
+	dir := t.TempDir()
+	for name, body := range map[string]string{
+		"partial.yaml": "retries:\n  max_retries: 5\n",
+		"partial.json": `{"retries": {"max_retries": 5}}`,
+	} {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(body), 0644))
+
+		config := &Config{}
+		require.NoError(t, ika.ReadConfig(path, config), name)
+		assert.Equal(t, helpers.RetryPolicy{MaxRetries: 5, BaseDelay: 500 * time.Millisecond, MaxDelay: 10 * time.Second}, config.retryPolicy(), name)
+	}
+
+	for name, body := range map[string]string{
+		"full.yaml": "retries:\n  max_retries: 2\n  base_delay: 1s\n  max_delay: 30s\n",
+		"full.json": `{"retries": {"max_retries": 2, "base_delay": "1s", "max_delay": "30s"}}`,
+	} {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(body), 0644))
+
+		config := &Config{}
+		require.NoError(t, ika.ReadConfig(path, config), name)
+		assert.Equal(t, helpers.RetryPolicy{MaxRetries: 2, BaseDelay: time.Second, MaxDelay: 30 * time.Second}, config.retryPolicy(), name)
+	}
+}
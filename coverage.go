@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/openware/binance-cli/pkg/opendax"
+)
+
+// CoverageRecord is a trading Binance market whose currencies are enabled on OpenDAX but which is not listed there
+type CoverageRecord struct {
+	BinanceSymbol          string   `json:"binance_symbol"`
+	BaseUnit               string   `json:"base_unit"`
+	QuoteUnit              string   `json:"quote_unit"`
+	OpendaxBaseCurrencies  []string `json:"opendax_base_currencies"`
+	OpendaxQuoteCurrencies []string `json:"opendax_quote_currencies"`
+}
+
+func (r *CoverageRecord) Columns() []string {
+	return []string{"binance_symbol", "base_unit", "quote_unit", "opendax_base_currencies", "opendax_quote_currencies"}
+}
+
+func (r *CoverageRecord) Values() []string {
+	return []string{
+		r.BinanceSymbol, r.BaseUnit, r.QuoteUnit,
+		strings.Join(r.OpendaxBaseCurrencies, " "), strings.Join(r.OpendaxQuoteCurrencies, " "),
+	}
+}
+
+func (r *CoverageRecord) PrintText(w io.Writer) {
+	fmt.Fprintf(w, "%s is not listed on OpenDAX, currencies: %s / %s\n",
+		r.BinanceSymbol, strings.Join(r.OpendaxBaseCurrencies, ","), strings.Join(r.OpendaxQuoteCurrencies, ","))
+}
+
+// enabledCoinCurrencies indexes the enabled OpenDAX currencies by the Binance coin they are mapped to
+func enabledCoinCurrencies(config *Config, opendaxCurrencies opendax.OpendaxCurrencies) map[string][]string {
+	coinCurrencies := make(map[string][]string)
+	for _, currency := range opendaxCurrencies {
+		if !currency.Enabled() {
+			continue
+		}
+		coin := config.Currencies.For(currency).Coin
+		coinCurrencies[coin] = append(coinCurrencies[coin], currency.Code)
+	}
+	return coinCurrencies
+}
+
+func marketsCoverage() error {
+	config := readConfig()
+
+	renderer, err := newRenderer(OutputFormat, os.Stdout, (&CoverageRecord{}).Columns())
+	if err != nil {
+		return err
+	}
+
+	binanceClient := newBinanceClient(config, "", "")
+	binanceInfo, err := binanceClient.ExchangeInfo()
+	if err != nil {
+		return err
+	}
+
+	opendaxClient := newOpendaxClient(config)
+	opendaxMarkets, err := opendaxClient.FetchOpendaxMarkets()
+	if err != nil {
+		return err
+	}
+
+	opendaxCurrencies, err := opendaxClient.FetchOpendaxCurrencies()
+	if err != nil {
+		return err
+	}
+
+	coinCurrencies := enabledCoinCurrencies(config, opendaxCurrencies)
+
+	// Binance markets already tracked by an OpenDAX market
+	listedSymbols := make(map[string]struct{})
+	for _, m := range opendaxMarkets {
+		listedSymbols[config.Markets.For(m).Symbol] = struct{}{}
+	}
+
+	missing := 0
+	for _, binanceMarket := range binanceInfo.Symbols {
+		if !binanceMarket.IsTrading() {
+			continue
+		}
+
+		if _, ok := listedSymbols[binanceMarket.Symbol]; ok {
+			continue
+		}
+
+		baseCurrencies, ok := coinCurrencies[binanceMarket.BaseUnit]
+		if !ok {
+			continue
+		}
+
+		quoteCurrencies, ok := coinCurrencies[binanceMarket.QuoteUnit]
+		if !ok {
+			continue
+		}
+
+		missing++
+		err := renderer.Render(&CoverageRecord{
+			BinanceSymbol:          binanceMarket.Symbol,
+			BaseUnit:               binanceMarket.BaseUnit,
+			QuoteUnit:              binanceMarket.QuoteUnit,
+			OpendaxBaseCurrencies:  baseCurrencies,
+			OpendaxQuoteCurrencies: quoteCurrencies,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(console(), "%d Binance markets could be listed on OpenDAX\n", missing)
+
+	return renderer.Close()
+}
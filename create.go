@@ -0,0 +1,222 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/openware/binance-cli/pkg/binance"
+	"github.com/openware/binance-cli/pkg/opendax"
+)
+
+// CreateQuoteUnit selects every Binance market quoted in this unit for `markets create`
+var CreateQuoteUnit = ""
+
+// CreateState is the initial state of the markets created by `markets create`
+var CreateState = "disabled"
+
+// CreatePosition is the position of the first market created by `markets create`, 0 lets OpenDAX choose
+var CreatePosition = 0
+
+// creationCandidates returns the Binance markets selected by symbol or by the quote unit filter
+func creationCandidates(binanceInfo *binance.BinanceExchangeInfo, symbols []string) []binance.BinanceMarket {
+	var candidates []binance.BinanceMarket
+
+	for _, symbol := range symbols {
+		binanceMarket, ok := binanceInfo.MarketRegistry[strings.ToUpper(symbol)]
+		if !ok {
+			fmt.Fprintln(console(), symbol, "is missing on Binance")
+			continue
+		}
+		candidates = append(candidates, binanceMarket)
+	}
+
+	if CreateQuoteUnit != "" {
+		for _, binanceMarket := range binanceInfo.Symbols {
+			if strings.EqualFold(binanceMarket.QuoteUnit, CreateQuoteUnit) {
+				candidates = append(candidates, binanceMarket)
+			}
+		}
+	}
+
+	return candidates
+}
+
+func createMarkets(symbols []string) error {
+	config := readConfig()
+
+	if len(symbols) == 0 && CreateQuoteUnit == "" {
+		return fmt.Errorf("no market to create, pass Binance symbols or a quote unit filter")
+	}
+
+	if err := binance.ValidatePriceSource(PriceSource); err != nil {
+		return err
+	}
+
+	if config.EngineId == 0 {
+		return fmt.Errorf("OPENDAX_ENGINE_ID is required to create markets")
+	}
+
+	binanceClient := newBinanceClient(config, "", "")
+	binanceInfo, err := binanceClient.ExchangeInfo()
+	if err != nil {
+		return err
+	}
+
+	opendaxClient := newAdminOpendaxClient(config)
+	opendaxMarkets, err := opendaxClient.FetchOpendaxMarkets()
+	if err != nil {
+		return err
+	}
+
+	opendaxCurrencies, err := opendaxClient.FetchOpendaxCurrencies()
+	if err != nil {
+		return err
+	}
+
+	// Binance markets already tracked by an OpenDAX market, and the OpenDAX currencies of every Binance coin
+	listedSymbols := make(map[string]struct{})
+	existingMarkets := make(map[string]struct{})
+	for _, m := range opendaxMarkets {
+		listedSymbols[config.Markets.For(m).Symbol] = struct{}{}
+		existingMarkets[m.Symbol] = struct{}{}
+	}
+	coinCurrencies := enabledCoinCurrencies(config, opendaxCurrencies)
+
+	summary := &marketsSummary{}
+	var banErr *binance.BanError
+	position := int64(CreatePosition)
+	candidates := creationCandidates(binanceInfo, symbols)
+
+	for _, binanceMarket := range candidates {
+		if !binanceMarket.IsTrading() {
+			fmt.Fprintln(console(), binanceMarket.Symbol, "is not trading on Binance, status:", binanceMarket.Status)
+			summary.add(binanceMarket.Symbol, OutcomeSkipped)
+			continue
+		}
+
+		if _, ok := listedSymbols[binanceMarket.Symbol]; ok {
+			fmt.Fprintln(console(), binanceMarket.Symbol, "already exists on OpenDAX, skipping")
+			summary.add(binanceMarket.Symbol, OutcomeSkipped)
+			continue
+		}
+
+		// Checked before converting the market, so skipped symbols cost no price request
+		baseCurrency, err := creationCurrency(coinCurrencies, binanceMarket.BaseUnit)
+		if err != nil {
+			fmt.Fprintf(console(), "%s: %s, skipping\n", binanceMarket.Symbol, err)
+			summary.add(binanceMarket.Symbol, OutcomeSkipped)
+			continue
+		}
+
+		quoteCurrency, err := creationCurrency(coinCurrencies, binanceMarket.QuoteUnit)
+		if err != nil {
+			fmt.Fprintf(console(), "%s: %s, skipping\n", binanceMarket.Symbol, err)
+			summary.add(binanceMarket.Symbol, OutcomeSkipped)
+			continue
+		}
+
+		symbol := baseCurrency + quoteCurrency
+		if _, ok := existingMarkets[symbol]; ok {
+			fmt.Fprintln(console(), symbol, "already exists on OpenDAX, skipping")
+			summary.add(binanceMarket.Symbol, OutcomeSkipped)
+			continue
+		}
+
+		notional := config.MarketPolicy.MinNotional.For(symbol, quoteCurrency)
+		convertedBinanceMarket, err := convertBinanceMarket(binanceClient, binanceMarket, false, notional)
+		// Every remaining market would fail the same way, stop and keep what was created so far
+		if errors.As(err, &banErr) {
+			break
+		}
+		if err != nil {
+			fmt.Fprintf(console(), "Error converting %s: %s\n", binanceMarket.Symbol, err)
+			summary.add(binanceMarket.Symbol, OutcomeFailed)
+			continue
+		}
+
+		// Mapped assets are listed under their OpenDAX currencies
+		convertedBinanceMarket.Symbol = symbol
+		convertedBinanceMarket.Name = strings.ToUpper(baseCurrency + "/" + quoteCurrency)
+		convertedBinanceMarket.BaseUnit = baseCurrency
+		convertedBinanceMarket.QuoteUnit = quoteCurrency
+
+		// A new market has no limits of its own, the policy decides whether it gets the Binance ones
+		for _, limit := range config.MarketPolicy.Apply(opendax.OpendaxMarket{}, convertedBinanceMarket) {
+			fmt.Fprintln(console(), convertedBinanceMarket.Symbol, "Binance limit not enforced:", limit)
+		}
+
+		fmt.Fprintln(console(), "Creating", convertedBinanceMarket.Symbol, "from", binanceMarket.Symbol)
+		convertedBinanceMarket.Fprint(console())
+
+		var input string
+		if AutoEnabled {
+			fmt.Fprintln(console(), "Skipping market creation prompt due to auto mode")
+		} else {
+			fmt.Fprint(console(), "Create this market?")
+			fmt.Scanln(&input)
+		}
+
+		if !AutoEnabled && input != "y" {
+			if input != "n" {
+				fmt.Fprintf(console(), "Wrong input %q, expected y or n, skipping\n", input)
+			}
+			summary.add(convertedBinanceMarket.Name, OutcomeSkipped)
+			continue
+		}
+
+		createdMarket, err := opendaxClient.CreateOpendaxMarket(opendax.CreateMarketRequest{
+			BaseCurrency:    convertedBinanceMarket.BaseUnit,
+			QuoteCurrency:   convertedBinanceMarket.QuoteUnit,
+			EngineId:        config.EngineId,
+			State:           CreateState,
+			Position:        position,
+			MinPrice:        convertedBinanceMarket.MinPrice,
+			MaxPrice:        convertedBinanceMarket.MaxPrice,
+			MinAmount:       convertedBinanceMarket.MinAmount,
+			MaxAmount:       optionalMaxAmount(convertedBinanceMarket),
+			AmountPrecision: convertedBinanceMarket.AmountPrecision,
+			PricePrecision:  convertedBinanceMarket.PricePrecision,
+		})
+		if err != nil {
+			fmt.Fprintf(console(), "Error creating %s: %s\n", convertedBinanceMarket.Symbol, err)
+			summary.add(convertedBinanceMarket.Name, OutcomeFailed)
+			continue
+		}
+
+		fmt.Fprintln(console(), "New market:")
+		createdMarket.Fprint(console())
+
+		summary.add(convertedBinanceMarket.Name, OutcomeCreated)
+		listedSymbols[binanceMarket.Symbol] = struct{}{}
+		existingMarkets[symbol] = struct{}{}
+		if position > 0 {
+			position++
+		}
+	}
+
+	if len(summary.markets(OutcomeCreated)) > 0 {
+		restartFinex(opendaxClient)
+	}
+
+	fmt.Fprintf(console(), "Created %d of %d Binance markets\n", len(summary.markets(OutcomeCreated)), len(candidates))
+	summary.Fprint(console())
+
+	if banErr != nil {
+		return withBinanceHint(banErr)
+	}
+	return summary.Err()
+}
+
+// creationCurrency returns the enabled OpenDAX currency mapped to a Binance coin, a coin mapped to several is ambiguous
+func creationCurrency(coinCurrencies map[string][]string, coin string) (string, error) {
+	currencies := coinCurrencies[coin]
+	switch len(currencies) {
+	case 0:
+		return "", fmt.Errorf("%s currency is missing on OpenDAX", coin)
+	case 1:
+		return currencies[0], nil
+	default:
+		return "", fmt.Errorf("%s is mapped to several OpenDAX currencies %v", coin, currencies)
+	}
+}
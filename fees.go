@@ -0,0 +1,321 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/openware/binance-cli/pkg/binance"
+	"github.com/openware/binance-cli/pkg/opendax"
+	"github.com/openware/binance-cli/pkg/policy"
+	"github.com/shopspring/decimal"
+)
+
+// FeeRecord is the comparison of an OpenDAX currency with the policy target derived from one Binance network
+type FeeRecord struct {
+	Currency           string           `json:"currency"`
+	BinanceCoin        string           `json:"binance_coin"`
+	Network            string           `json:"network"`
+	BinanceMinWithdraw *decimal.Decimal `json:"binance_min_withdraw"`
+	TargetMinWithdraw  *decimal.Decimal `json:"target_min_withdraw"`
+	OpendaxMinWithdraw decimal.Decimal  `json:"opendax_min_withdraw"`
+	MinWithdrawDelta   *decimal.Decimal `json:"min_withdraw_delta"`
+	MinWithdrawVerdict string           `json:"min_withdraw_verdict"`
+	BinanceWithdrawFee *decimal.Decimal `json:"binance_withdraw_fee"`
+	TargetWithdrawFee  *decimal.Decimal `json:"target_withdraw_fee"`
+	OpendaxWithdrawFee decimal.Decimal  `json:"opendax_withdraw_fee"`
+	WithdrawFeeDelta   *decimal.Decimal `json:"withdraw_fee_delta"`
+	WithdrawFeeVerdict string           `json:"withdraw_fee_verdict"`
+	Verdict            string           `json:"verdict"`
+}
+
+func newFeeRecord(currency *opendax.OpendaxCurrency, coin string, network *binance.Network, feePolicy policy.FeePolicy) *FeeRecord {
+	record := &FeeRecord{
+		Currency:           currency.Code,
+		BinanceCoin:        coin,
+		OpendaxMinWithdraw: currency.MinWithdrawAmount,
+		OpendaxWithdrawFee: currency.WithdrawFee,
+		Verdict:            VerdictMissing,
+	}
+
+	if network == nil {
+		return record
+	}
+
+	targetMinWithdraw := feePolicy.Target(network.WithdrawMin)
+	targetWithdrawFee := feePolicy.Target(network.WithdrawFee)
+	minWithdrawDelta := currency.MinWithdrawAmount.Sub(targetMinWithdraw)
+	withdrawFeeDelta := currency.WithdrawFee.Sub(targetWithdrawFee)
+
+	record.Network = network.Name
+	record.BinanceMinWithdraw = &network.WithdrawMin
+	record.TargetMinWithdraw = &targetMinWithdraw
+	record.MinWithdrawDelta = &minWithdrawDelta
+	record.MinWithdrawVerdict = feeVerdict(minWithdrawDelta)
+	record.BinanceWithdrawFee = &network.WithdrawFee
+	record.TargetWithdrawFee = &targetWithdrawFee
+	record.WithdrawFeeDelta = &withdrawFeeDelta
+	record.WithdrawFeeVerdict = feeVerdict(withdrawFeeDelta)
+
+	switch {
+	case record.MinWithdrawVerdict == VerdictTooLow || record.WithdrawFeeVerdict == VerdictTooLow:
+		record.Verdict = VerdictTooLow
+	case record.MinWithdrawVerdict == VerdictTooHigh || record.WithdrawFeeVerdict == VerdictTooHigh:
+		record.Verdict = VerdictTooHigh
+	default:
+		record.Verdict = VerdictOk
+	}
+
+	return record
+}
+
+func feeVerdict(delta decimal.Decimal) string {
+	switch delta.Sign() {
+	case -1:
+		return VerdictTooLow
+	case 1:
+		return VerdictTooHigh
+	default:
+		return VerdictOk
+	}
+}
+
+func (r *FeeRecord) Columns() []string {
+	return []string{
+		"currency", "binance_coin", "network",
+		"binance_min_withdraw", "target_min_withdraw", "opendax_min_withdraw", "min_withdraw_delta", "min_withdraw_verdict",
+		"binance_withdraw_fee", "target_withdraw_fee", "opendax_withdraw_fee", "withdraw_fee_delta", "withdraw_fee_verdict",
+		"verdict",
+	}
+}
+
+func (r *FeeRecord) Values() []string {
+	return []string{
+		r.Currency, r.BinanceCoin, r.Network,
+		optionalDecimal(r.BinanceMinWithdraw), optionalDecimal(r.TargetMinWithdraw), r.OpendaxMinWithdraw.String(), optionalDecimal(r.MinWithdrawDelta), r.MinWithdrawVerdict,
+		optionalDecimal(r.BinanceWithdrawFee), optionalDecimal(r.TargetWithdrawFee), r.OpendaxWithdrawFee.String(), optionalDecimal(r.WithdrawFeeDelta), r.WithdrawFeeVerdict,
+		r.Verdict,
+	}
+}
+
+func (r *FeeRecord) PrintText(w io.Writer) {
+	if r.Verdict == VerdictMissing && r.Network != "" {
+		color.New(color.FgYellow).Fprintf(w, "\n%s coin on %s network cannot be found on Binance, skipping ...\n", r.BinanceCoin, r.Network)
+		return
+	}
+
+	if r.Verdict == VerdictMissing {
+		color.New(color.FgYellow).Fprintf(w, "\n%s cannot be found on Binance, skipping ...\n", r.BinanceCoin)
+		return
+	}
+
+	fmt.Fprintf(w, "\n%s coin on %s network:\n", r.BinanceCoin, r.Network)
+	printFeeCondition(w, "MinWithdraw", r.MinWithdrawVerdict, r.OpendaxMinWithdraw, *r.BinanceMinWithdraw, *r.TargetMinWithdraw)
+	printFeeCondition(w, "WithdrawFee", r.WithdrawFeeVerdict, r.OpendaxWithdrawFee, *r.BinanceWithdrawFee, *r.TargetWithdrawFee)
+}
+
+func printFeeCondition(w io.Writer, name, verdict string, opendaxValue, binanceValue, targetValue decimal.Decimal) {
+	opendaxFloat, _ := opendaxValue.Float64()
+	binanceFloat, _ := binanceValue.Float64()
+	targetFloat, _ := targetValue.Float64()
+
+	switch verdict {
+	case VerdictOk:
+		color.New(color.FgGreen).Fprintf(w, "%s amount satisfy condition\nOpendax: %f; Binance: %f; Target: %f;\n", name, opendaxFloat, binanceFloat, targetFloat)
+	case VerdictTooHigh:
+		color.New(color.FgYellow).Fprintf(w, "%s amount is above the target\nOpendax: %f; Binance: %f; Target: %f;\n", name, opendaxFloat, binanceFloat, targetFloat)
+	default:
+		color.New(color.FgRed).Fprintf(w, "%s amount DOES NOT satisfy condition!\nOpendax: %f; Binance: %f; Target: %f;\n", name, opendaxFloat, binanceFloat, targetFloat)
+	}
+}
+
+func optionalDecimal(d *decimal.Decimal) string {
+	if d == nil {
+		return ""
+	}
+	return d.String()
+}
+
+// withBinanceHint appends what the user can do about a well-known Binance error
+func withBinanceHint(err error) error {
+	if hint := binance.Hint(err); hint != "" {
+		return fmt.Errorf("%w (hint: %s)", err, hint)
+	}
+	return err
+}
+
+// fetchFeeSources fetches OpenDAX currencies and Binance coins indexed by their code
+func fetchFeeSources(config *Config, opendaxClient *opendax.OpendaxClient) (opendax.OpendaxCurrencies, map[string]*binance.BinanceCurrency, error) {
+	opendaxCurrencies, err := opendaxClient.FetchOpendaxCurrencies()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	binanceClient := newBinanceClient(config, config.BinanceApiKey, config.BinanceSecret)
+	binanceCurrencies, err := binanceClient.CoinsInfo()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Save Binance Currencies info as Map to optimize search
+	binanceCoinsRegistry := make(map[string]*binance.BinanceCurrency)
+	for _, coin := range binanceCurrencies {
+		binanceCoinsRegistry[coin.Code] = coin
+	}
+
+	return opendaxCurrencies, binanceCoinsRegistry, nil
+}
+
+// currencyFeeRecords compares an OpenDAX currency against its mapped network of the Binance coin,
+// or against every network of the coin when the mapping has no network
+func currencyFeeRecords(config *Config, opendaxCurrency *opendax.OpendaxCurrency, binanceCoinsRegistry map[string]*binance.BinanceCurrency) []*FeeRecord {
+	feePolicy := config.Fees.For(opendaxCurrency.Code)
+	coin := config.Currencies.For(opendaxCurrency)
+
+	binanceCurrency := binanceCoinsRegistry[coin.Coin]
+	if binanceCurrency == nil {
+		return []*FeeRecord{newFeeRecord(opendaxCurrency, coin.Coin, nil, feePolicy)}
+	}
+
+	records := make([]*FeeRecord, 0, len(binanceCurrency.Networks))
+	for i, network := range binanceCurrency.Networks {
+		if coin.Network != "" && !strings.EqualFold(network.Name, coin.Network) {
+			continue
+		}
+		records = append(records, newFeeRecord(opendaxCurrency, binanceCurrency.Code, &binanceCurrency.Networks[i], feePolicy))
+	}
+
+	if len(records) == 0 {
+		record := newFeeRecord(opendaxCurrency, binanceCurrency.Code, nil, feePolicy)
+		record.Network = coin.Network
+		records = append(records, record)
+	}
+
+	return records
+}
+
+// feeSyncRequest raises the currency fees to the policy target of every compared network, it returns nil when nothing is too low
+func feeSyncRequest(opendaxCurrency *opendax.OpendaxCurrency, records []*FeeRecord) *opendax.UpdateCurrencyRequest {
+	request := &opendax.UpdateCurrencyRequest{
+		Code:              opendaxCurrency.Code,
+		WithdrawFee:       opendaxCurrency.WithdrawFee,
+		MinWithdrawAmount: opendaxCurrency.MinWithdrawAmount,
+	}
+
+	tooLow := false
+	for _, record := range records {
+		if record.Verdict != VerdictTooLow {
+			continue
+		}
+		tooLow = true
+		request.WithdrawFee = decimal.Max(request.WithdrawFee, *record.TargetWithdrawFee)
+		request.MinWithdrawAmount = decimal.Max(request.MinWithdrawAmount, *record.TargetMinWithdraw)
+	}
+
+	if !tooLow {
+		return nil
+	}
+	return request
+}
+
+func compareFees() error {
+	config := readConfig()
+
+	renderer, err := newRenderer(OutputFormat, os.Stdout, (&FeeRecord{}).Columns())
+	if err != nil {
+		return err
+	}
+
+	opendaxClient := newOpendaxClient(config)
+	opendaxCurrencies, binanceCoinsRegistry, err := fetchFeeSources(config, opendaxClient)
+	if err != nil {
+		return withBinanceHint(err)
+	}
+
+	for _, opendaxCurrency := range opendaxCurrencies {
+		for _, record := range currencyFeeRecords(config, opendaxCurrency, binanceCoinsRegistry) {
+			if err := renderer.Render(record); err != nil {
+				return err
+			}
+		}
+	}
+
+	return renderer.Close()
+}
+
+func syncFees() error {
+	config := readConfig()
+
+	renderer, err := newRenderer(OutputFormat, os.Stdout, (&FeeRecord{}).Columns())
+	if err != nil {
+		return err
+	}
+
+	opendaxClient := newAdminOpendaxClient(config)
+	opendaxCurrencies, binanceCoinsRegistry, err := fetchFeeSources(config, opendaxClient)
+	if err != nil {
+		return withBinanceHint(err)
+	}
+
+	var updatedCurrencies, failedCurrencies []string
+
+	for _, opendaxCurrency := range opendaxCurrencies {
+		records := currencyFeeRecords(config, opendaxCurrency, binanceCoinsRegistry)
+		for _, record := range records {
+			if err := renderer.Render(record); err != nil {
+				return err
+			}
+		}
+
+		request := feeSyncRequest(opendaxCurrency, records)
+		if request == nil {
+			continue
+		}
+
+		fmt.Fprintf(console(), "\nProposed %s fees:\nWithdrawFee: %s -> %s\nMinWithdraw: %s -> %s\n",
+			opendaxCurrency.Code,
+			opendaxCurrency.WithdrawFee, request.WithdrawFee,
+			opendaxCurrency.MinWithdrawAmount, request.MinWithdrawAmount)
+
+		var input string
+		if AutoEnabled {
+			fmt.Fprintln(console(), "Skipping currency update prompt due to auto mode")
+		} else {
+			fmt.Fprint(console(), "Update this currency?")
+			fmt.Scanln(&input)
+		}
+
+		if !AutoEnabled && input != "y" {
+			if input != "n" {
+				fmt.Fprintf(console(), "Wrong input %q, expected y or n, skipping\n", input)
+			}
+			continue
+		}
+
+		updatedCurrency, err := opendaxClient.UpdateOpendaxCurrency(*request)
+		if err != nil {
+			fmt.Fprintf(console(), "Error updating %s: %s\n", opendaxCurrency.Code, err)
+			failedCurrencies = append(failedCurrencies, opendaxCurrency.Code)
+			continue
+		}
+
+		fmt.Fprintf(console(), "New %s fees:\nWithdrawFee: %s\nMinWithdraw: %s\n", opendaxCurrency.Code, updatedCurrency.WithdrawFee, updatedCurrency.MinWithdrawAmount)
+
+		updatedCurrencies = append(updatedCurrencies, opendaxCurrency.Code)
+	}
+
+	if err := renderer.Close(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(console(), "\nUpdated %d of %d OpenDAX currencies: %v\n", len(updatedCurrencies), len(opendaxCurrencies), updatedCurrencies)
+
+	if len(failedCurrencies) > 0 {
+		color.New(color.FgRed).Fprintf(console(), "Failed to update %d currencies: %v\n", len(failedCurrencies), failedCurrencies)
+		return fmt.Errorf("%d currencies failed: %v", len(failedCurrencies), failedCurrencies)
+	}
+
+	return nil
+}
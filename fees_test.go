@@ -0,0 +1,124 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/openware/binance-cli/pkg/binance"
+	"github.com/openware/binance-cli/pkg/opendax"
+	"github.com/openware/binance-cli/pkg/policy"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustDecimal(value string) decimal.Decimal {
+	return decimal.RequireFromString(value)
+}
+
+func TestFeeSync(t *testing.T) {
+	markup := mustDecimal("10")
+
+	tests := []struct {
+		name              string
+		withdrawFee       string
+		minWithdraw       string
+		networks          []binance.Network
+		policy            policy.FeePolicy
+		verdicts          []string
+		syncedWithdrawFee string
+		syncedMinWithdraw string
+	}{
+		{
+			name:        "equal fees",
+			withdrawFee: "0.005", minWithdraw: "0.01",
+			networks: []binance.Network{{Name: "ETH", WithdrawFee: mustDecimal("0.005"), WithdrawMin: mustDecimal("0.01")}},
+			verdicts: []string{VerdictOk},
+		},
+		{
+			name:        "fees above target are left as they are",
+			withdrawFee: "0.01", minWithdraw: "0.02",
+			networks: []binance.Network{{Name: "ETH", WithdrawFee: mustDecimal("0.005"), WithdrawMin: mustDecimal("0.01")}},
+			verdicts: []string{VerdictTooHigh},
+		},
+		{
+			name:        "withdraw fee below target",
+			withdrawFee: "0.001", minWithdraw: "0.01",
+			networks:          []binance.Network{{Name: "ETH", WithdrawFee: mustDecimal("0.005"), WithdrawMin: mustDecimal("0.01")}},
+			verdicts:          []string{VerdictTooLow},
+			syncedWithdrawFee: "0.005", syncedMinWithdraw: "0.01",
+		},
+		{
+			name:        "min withdraw below target",
+			withdrawFee: "0.005", minWithdraw: "0.001",
+			networks:          []binance.Network{{Name: "ETH", WithdrawFee: mustDecimal("0.005"), WithdrawMin: mustDecimal("0.01")}},
+			verdicts:          []string{VerdictTooLow},
+			syncedWithdrawFee: "0.005", syncedMinWithdraw: "0.01",
+		},
+		{
+			name:        "min withdraw above target is not lowered while the fee is raised",
+			withdrawFee: "0.001", minWithdraw: "0.05",
+			networks:          []binance.Network{{Name: "ETH", WithdrawFee: mustDecimal("0.005"), WithdrawMin: mustDecimal("0.01")}},
+			verdicts:          []string{VerdictTooLow},
+			syncedWithdrawFee: "0.005", syncedMinWithdraw: "0.05",
+		},
+		{
+			name:        "target includes the markup",
+			withdrawFee: "0.005", minWithdraw: "0.01",
+			networks:          []binance.Network{{Name: "ETH", WithdrawFee: mustDecimal("0.005"), WithdrawMin: mustDecimal("0.01")}},
+			policy:            policy.FeePolicy{Markup: &markup},
+			verdicts:          []string{VerdictTooLow},
+			syncedWithdrawFee: "0.0055", syncedMinWithdraw: "0.011",
+		},
+		{
+			name:        "raised to the highest target of the networks too low",
+			withdrawFee: "0.002", minWithdraw: "0.01",
+			networks: []binance.Network{
+				{Name: "ETH", WithdrawFee: mustDecimal("0.005"), WithdrawMin: mustDecimal("0.01")},
+				{Name: "BSC", WithdrawFee: mustDecimal("0.0001"), WithdrawMin: mustDecimal("0.001")},
+				{Name: "ARBITRUM", WithdrawFee: mustDecimal("0.003"), WithdrawMin: mustDecimal("0.02")},
+			},
+			verdicts:          []string{VerdictTooLow, VerdictTooHigh, VerdictTooLow},
+			syncedWithdrawFee: "0.005", syncedMinWithdraw: "0.02",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			currency := &opendax.OpendaxCurrency{Code: "eth", WithdrawFee: mustDecimal(tt.withdrawFee), MinWithdrawAmount: mustDecimal(tt.minWithdraw)}
+
+			records := make([]*FeeRecord, len(tt.networks))
+			for i := range tt.networks {
+				records[i] = newFeeRecord(currency, "ETH", &tt.networks[i], tt.policy)
+				assert.Equal(t, tt.verdicts[i], records[i].Verdict, tt.networks[i].Name)
+			}
+
+			request := feeSyncRequest(currency, records)
+			if tt.syncedWithdrawFee == "" {
+				assert.Nil(t, request)
+				return
+			}
+
+			require.NotNil(t, request)
+			assert.Equal(t, "eth", request.Code)
+			assert.Equal(t, tt.syncedWithdrawFee, request.WithdrawFee.String())
+			assert.Equal(t, tt.syncedMinWithdraw, request.MinWithdrawAmount.String())
+		})
+	}
+}
+
+func TestFeeRecordVerdicts(t *testing.T) {
+	currency := &opendax.OpendaxCurrency{Code: "eth", WithdrawFee: mustDecimal("0.01"), MinWithdrawAmount: mustDecimal("0.001")}
+	network := &binance.Network{Name: "ETH", WithdrawFee: mustDecimal("0.005"), WithdrawMin: mustDecimal("0.01")}
+
+	record := newFeeRecord(currency, "ETH", network, policy.FeePolicy{})
+	assert.Equal(t, VerdictTooHigh, record.WithdrawFeeVerdict)
+	assert.Equal(t, "0.005", record.WithdrawFeeDelta.String())
+	assert.Equal(t, VerdictTooLow, record.MinWithdrawVerdict)
+	assert.Equal(t, "-0.009", record.MinWithdrawDelta.String())
+	// A too low value wins, it is the one to sync
+	assert.Equal(t, VerdictTooLow, record.Verdict)
+
+	missing := newFeeRecord(currency, "ETH", nil, policy.FeePolicy{})
+	assert.Equal(t, VerdictMissing, missing.Verdict)
+	assert.Nil(t, feeSyncRequest(currency, []*FeeRecord{missing}))
+}
@@ -3,13 +3,9 @@ package main
 import (
 	"fmt"
 	"os"
-	"time"
 
-	"github.com/fatih/color"
 	"github.com/openware/binance-cli/pkg/binance"
-	"github.com/openware/binance-cli/pkg/helpers"
 	"github.com/openware/binance-cli/pkg/opendax"
-	"github.com/shopspring/decimal"
 
 	"github.com/openware/pkg/kli"
 )
@@ -17,20 +13,62 @@ import (
 // version of the command line
 var version = "SNAPSHOT"
 
-// AutoEnabled defines whether auto mode should be used for the markets cmd
+// AutoEnabled defines whether auto mode should be used for the markets and fees sync cmds
 var AutoEnabled = false
 
+// DisableNotTrading defines whether markets cmd should propose disabling OpenDAX markets not trading on Binance
+var DisableNotTrading = false
+
+// DryRunEnabled defines whether OpenDAX write requests should only be logged instead of sent
+var DryRunEnabled = false
+
 func main() {
 	cli := kli.NewCli("binance-cli", "Binance cli", version)
+	cli.StringFlag("config", "Path to a YAML or JSON config file", &ConfigPath)
+	cli.StringFlag("output", "Output format of the fees and markets comparison: text, json, ndjson or csv", &OutputFormat)
 
 	feesCommand := kli.NewCommand("fees", "Compare fees").Action(compareFees)
 	cli.DefaultCommand(feesCommand)
 	cli.AddCommand(feesCommand)
 
+	feesSyncCommand := feesCommand.NewSubCommand("sync", "Raise OpenDAX fees below the Binance network fees").Action(syncFees)
+	feesSyncCommand.BoolFlag("auto", "Automatically update every currency", &AutoEnabled)
+	feesSyncCommand.BoolFlag("dry-run", "Log the OpenDAX update requests instead of sending them", &DryRunEnabled)
+
 	marketsCommand := kli.NewCommand("markets", "Compare markets").Action(compareMarkets)
 	cli.AddCommand(marketsCommand)
 
 	marketsCommand.BoolFlag("auto", "Automatically update every market and save the output", &AutoEnabled)
+	marketsCommand.BoolFlag("dry-run", "Log the OpenDAX update requests instead of sending them", &DryRunEnabled)
+	marketsCommand.BoolFlag("disable-not-trading", "Propose disabling markets whose Binance reference is not trading", &DisableNotTrading)
+	marketsCommand.StringFlag("fields", "Comma separated market fields to compare, every field by default", &CompareFields)
+	marketsCommand.StringFlag("price-source", "Binance price the min amount is derived from: last, avg, vwap or mid", &PriceSource)
+
+	marketsPlanCommand := marketsCommand.NewSubCommand("plan", "Write proposed market changes to a plan file [default: "+DefaultPlanPath+"]")
+	marketsPlanCommand.Action(func() error {
+		return planMarkets(marketsPlanCommand.OtherArgs())
+	})
+	marketsPlanCommand.StringFlag("fields", "Comma separated market fields to compare, every field by default", &CompareFields)
+	marketsPlanCommand.StringFlag("price-source", "Binance price the min amount is derived from: last, avg, vwap or mid", &PriceSource)
+
+	marketsApplyCommand := marketsCommand.NewSubCommand("apply", "Apply the reviewed changes of a plan file")
+	marketsApplyCommand.Action(func() error {
+		return applyMarkets(marketsApplyCommand.OtherArgs())
+	})
+	marketsApplyCommand.BoolFlag("dry-run", "Log the OpenDAX update requests instead of sending them", &DryRunEnabled)
+
+	marketsCommand.NewSubCommand("coverage", "List Binance markets of enabled OpenDAX currencies missing on OpenDAX").Action(marketsCoverage)
+
+	marketsCreateCommand := marketsCommand.NewSubCommand("create", "Create OpenDAX markets from the given Binance symbols")
+	marketsCreateCommand.Action(func() error {
+		return createMarkets(marketsCreateCommand.OtherArgs())
+	})
+	marketsCreateCommand.StringFlag("quote", "Create every Binance market quoted in this unit", &CreateQuoteUnit)
+	marketsCreateCommand.StringFlag("state", "Initial state of the created markets", &CreateState)
+	marketsCreateCommand.IntFlag("position", "Position of the first created market, incremented for the next ones", &CreatePosition)
+	marketsCreateCommand.BoolFlag("auto", "Automatically create every market", &AutoEnabled)
+	marketsCreateCommand.StringFlag("price-source", "Binance price the min amount is derived from: last, avg, vwap or mid", &PriceSource)
+	marketsCreateCommand.BoolFlag("dry-run", "Log the OpenDAX create requests instead of sending them", &DryRunEnabled)
 
 	if err := cli.Run(); err != nil {
 		fmt.Printf("Error encountered: %v\n", err)
@@ -38,168 +76,27 @@ func main() {
 	}
 }
 
-func compareMarkets() error {
-	config := readConfig()
-	binanceClient := binance.NewBinanceClient("", "", binance.BinanceBaseUrl)
-	binanceInfo, err := binanceClient.ExchangeInfo()
-	if err != nil {
-		return err
-	}
-
-	opendaxClient := opendax.NewOpendaxClient(config.PlatformBaseUrl)
-	opendaxClient.Authorize(config.OpendaxApiKey, config.OpendaxApiSecret)
-	opendaxMarkets, err := opendaxClient.FetchOpendaxMarkets()
-	if err != nil {
-		return err
-	}
-
-	var updatedMarkets []string
-
-	for _, opendaxMarket := range opendaxMarkets {
-		binanceMarket, ok := binanceInfo.MarketRegistry[opendaxMarket.ToBinanceMarketName()]
-		if ok {
-			tickerPrice, err := binanceClient.TickerPriceInfo(binanceMarket.Symbol)
-			if err != nil {
-				fmt.Printf("ERR: compareMarkets: ticker price fetch for %s failed: %s\n", binanceMarket.Symbol, err)
-				continue
-			}
-
-			minAmount := binanceMarket.CalculateMinAmount(tickerPrice.Price)
-			if minAmount.Equal(decimal.Zero) {
-				fmt.Printf("ERR: compareMarkets: min amount is zero for %s!\n", binanceMarket.Symbol)
-				continue
-			}
-
-			convertedBinanceMarket, err := binanceMarket.ToOpendaxMarket(minAmount)
-			if err != nil {
-				fmt.Printf("Error: %s, Skipping\n", err.Error())
-				continue
-			}
-			fmt.Println("Comparing", opendaxMarket.Symbol)
-			fmt.Println("Equal:", opendax.CompareOpendaxMarkets(&opendaxMarket, convertedBinanceMarket))
-			fmt.Println("Binance:")
-			convertedBinanceMarket.Print()
-			fmt.Println("Opendax:")
-			opendaxMarket.Print()
-			fmt.Println("")
-
-			if opendax.CompareOpendaxMarkets(&opendaxMarket, convertedBinanceMarket) {
-				fmt.Println("Skipping")
-				continue
-			}
-
-			var input string
-			if AutoEnabled {
-				fmt.Println("Skipping market update prompt due to auto mode")
-			} else {
-				fmt.Print("Update this market?")
-				fmt.Scanln(&input)
-			}
-
-			if AutoEnabled || input == "y" {
-				updatedMarket, err := opendaxClient.UpdateOpendaxMarket(opendax.UpdateMarketRequest{
-					Symbol:          opendaxMarket.Symbol,
-					MinPrice:        convertedBinanceMarket.MinPrice,
-					MaxPrice:        convertedBinanceMarket.MaxPrice,
-					MinAmount:       convertedBinanceMarket.MinAmount,
-					AmountPrecision: convertedBinanceMarket.AmountPrecision,
-					PricePrecision:  convertedBinanceMarket.PricePrecision,
-				})
-
-				if err != nil {
-					panic(err)
-				}
-
-				fmt.Println("New market:")
-				updatedMarket.Print()
-
-				updatedMarkets = append(updatedMarkets, updatedMarket.Name)
-			} else if input == "n" {
-				continue
-			} else {
-				panic("Wrong input")
-			}
-		} else {
-			fmt.Println(opendaxMarket.Symbol, "is missing on Binance")
-		}
-	}
-
-	if AutoEnabled && len(updatedMarkets) > 0 {
-		err = helpers.WriteToFile("updated-markets.txt", fmt.Sprintf("%v", updatedMarkets))
-		if err != nil {
-			fmt.Printf("Error saving updated markets: %s\nUpdated markets: %v", err, updatedMarkets)
-		}
-
-		secretUpdateParams := opendax.UpdateSecretRequest{
-			Scope: "private",
-			Key:   "restart",
-			Value: fmt.Sprint(time.Now()),
-		}
-
-		err := opendaxClient.UpdateOpendaxSecret(secretUpdateParams)
-
-		if err != nil {
-			fmt.Printf("Error updating Finex restart secret: %s", err)
-		}
-	}
-
-	fmt.Println("Total OpenDAX markets:", len(opendaxMarkets))
-
-	return nil
+// newBinanceClient returns a Binance client retrying failed requests as configured, keys are only needed for signed endpoints
+func newBinanceClient(config *Config, apiKey, secret string) *binance.BinanceClient {
+	binanceClient := binance.NewBinanceClient(apiKey, secret, binance.BinanceBaseUrl)
+	binanceClient.SetRetryPolicy(config.retryPolicy())
+	binanceClient.SetRecvWindow(config.BinanceRecvWindow)
+	return binanceClient
 }
 
-func compareFees() error {
-	config := readConfig()
-
+// newOpendaxClient returns an OpenDAX client for the public endpoints, retrying failed requests as configured
+func newOpendaxClient(config *Config) *opendax.OpendaxClient {
 	opendaxClient := opendax.NewOpendaxClient(config.PlatformBaseUrl)
-	opendaxCurrencies, err := opendaxClient.FetchOpendaxCurrencies()
-	if err != nil {
-		return err
-	}
-
-	binanceClient := binance.NewBinanceClient(config.BinanceApiKey, config.BinanceSecret, binance.BinanceBaseUrl)
-	binanceCurrencies, err := binanceClient.CoinsInfo()
-	if err != nil {
-		return err
-	}
-
-	// Save Binance Currencies info as Map to optimize search
-	binanceCoinsRegistry := make(map[string]*binance.BinanceCurrency)
-	for _, coin := range binanceCurrencies {
-		binanceCoinsRegistry[coin.Code] = coin
-	}
-
-	for _, opendaxCurrency := range opendaxCurrencies {
-		binanceCurrency := binanceCoinsRegistry[opendaxCurrency.ToBinanceCoinName()]
-		if binanceCurrency == nil {
-			color.Yellow(fmt.Sprintf("\n%s cannot be found on Binance, skipping ...\n", opendaxCurrency.ToBinanceCoinName()))
-			continue
-		}
-
-		for _, network := range binanceCurrency.Networks {
-			fmt.Printf("\n%s coin on %s network:\n", opendaxCurrency.ToBinanceCoinName(), network.Name)
-
-			opendaxMinWithdraw, _ := opendaxCurrency.MinWithdrawAmount.Float64()
-
-			binanceMinWithdraw, _ := network.WithdrawMin.Float64()
-
-			if opendaxMinWithdraw >= binanceMinWithdraw {
-				color.Green(fmt.Sprintf("MinWithdraw amount satisfy condition\nOpendax: %f; Binance: %f;\n", opendaxMinWithdraw, binanceMinWithdraw))
-			} else {
-				color.Red(fmt.Sprintf("MinWithdraw amount DOES NOT satisfy condition!\nOpendax: %f; Binance: %f;\n", opendaxMinWithdraw, binanceMinWithdraw))
-			}
-
-			opendaxWithdrawFee, _ := opendaxCurrency.WithdrawFee.Float64()
-
-			binanceWithdrawFee, _ := network.WithdrawFee.Float64()
+	opendaxClient.SetRetryPolicy(config.retryPolicy())
+	return opendaxClient
+}
 
-			if opendaxWithdrawFee >= binanceWithdrawFee {
-				color.Green(fmt.Sprintf("WithdrawFee amount satisfy condition\nOpendax: %f; Binance: %f;\n", opendaxWithdrawFee, binanceWithdrawFee))
-			} else {
-				color.Red(fmt.Sprintf("WithdrawFee amount DOES NOT satisfy condition!\nOpendax: %f; Binance: %f;\n", opendaxWithdrawFee, binanceWithdrawFee))
-			}
-		}
+// newAdminOpendaxClient returns an authorized OpenDAX client, which only logs write requests in dry run mode
+func newAdminOpendaxClient(config *Config) *opendax.OpendaxClient {
+	opendaxClient := newOpendaxClient(config)
+	opendaxClient.Authorize(config.OpendaxApiKey, config.OpendaxApiSecret)
+	if DryRunEnabled {
+		opendaxClient.DryRun(console())
 	}
-
-	return nil
+	return opendaxClient
 }
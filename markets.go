@@ -0,0 +1,492 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/openware/binance-cli/pkg/binance"
+	"github.com/openware/binance-cli/pkg/helpers"
+	"github.com/openware/binance-cli/pkg/mapping"
+	"github.com/openware/binance-cli/pkg/opendax"
+	"github.com/openware/binance-cli/pkg/policy"
+	"github.com/shopspring/decimal"
+)
+
+// MarketRecord is the comparison of an OpenDAX market with the configuration derived from Binance
+type MarketRecord struct {
+	Market        string                `json:"market"`
+	BinanceSymbol string                `json:"binance_symbol"`
+	Inverted      bool                  `json:"inverted"`
+	BinanceStatus string                `json:"binance_status"`
+	Permissions   []string              `json:"binance_permissions"`
+	Opendax       opendax.OpendaxMarket `json:"opendax"`
+	// Binance is the market converted from Binance, before the market policy
+	Binance               *opendax.OpendaxMarket `json:"binance"`
+	MinAmountDelta        *decimal.Decimal       `json:"min_amount_delta"`
+	MinNotionalMultiplier decimal.Decimal        `json:"min_notional_multiplier"`
+	MinNotionalFloor      decimal.Decimal        `json:"min_notional_floor"`
+	Diff                  opendax.MarketDiff     `json:"diff,omitempty"`
+	Verdict               string                 `json:"verdict"`
+	Error                 string                 `json:"error,omitempty"`
+	Warnings              []string               `json:"warnings,omitempty"`
+	Unenforced            []string               `json:"unenforced,omitempty"`
+}
+
+// newMarketRecord reports the converted Binance values as they are, the diff is taken against the target the policy settled on
+func newMarketRecord(opendaxMarket opendax.OpendaxMarket, reference mapping.Market, binanceMarket *binance.BinanceMarket, converted, target *opendax.OpendaxMarket, unenforced []string, fields []string, err error) *MarketRecord {
+	record := &MarketRecord{
+		Market:        opendaxMarket.Symbol,
+		BinanceSymbol: reference.Symbol,
+		Inverted:      reference.Inverted,
+		Opendax:       opendaxMarket,
+		Binance:       converted,
+		Unenforced:    unenforced,
+	}
+
+	if binanceMarket != nil {
+		record.BinanceStatus = binanceMarket.Status
+		record.Permissions = binanceMarket.Permissions
+		record.Warnings = binanceMarket.Inconsistencies()
+	}
+
+	switch {
+	case binanceMarket == nil:
+		record.Verdict = VerdictMissing
+	case !binanceMarket.IsTrading():
+		record.Verdict = VerdictNotTrading
+	case err != nil:
+		record.Verdict = VerdictError
+		record.Error = err.Error()
+	default:
+		delta := opendaxMarket.MinAmount.Sub(converted.MinAmount)
+		record.MinAmountDelta = &delta
+		record.Diff = opendax.DiffOpendaxMarkets(&opendaxMarket, target, fields)
+		record.Verdict = VerdictDifferent
+		if len(record.Diff) == 0 {
+			record.Verdict = VerdictEqual
+		}
+	}
+
+	return record
+}
+
+func (r *MarketRecord) Columns() []string {
+	return []string{
+		"market", "binance_symbol", "inverted", "binance_status", "binance_permissions",
+		"binance_min_price", "opendax_min_price",
+		"binance_max_price", "opendax_max_price",
+		"binance_min_amount", "opendax_min_amount", "min_amount_delta",
+		"min_notional_multiplier", "min_notional_floor",
+		"binance_max_amount", "opendax_max_amount",
+		"binance_amount_precision", "opendax_amount_precision",
+		"binance_price_precision", "opendax_price_precision",
+		"diff", "verdict", "error", "warnings", "unenforced",
+	}
+}
+
+func (r *MarketRecord) Values() []string {
+	binanceValues := make([]string, 6)
+	if r.Binance != nil {
+		binanceValues = []string{
+			r.Binance.MinPrice.String(),
+			r.Binance.MaxPrice.String(),
+			r.Binance.MinAmount.String(),
+			r.Binance.MaxAmount.String(),
+			fmt.Sprint(r.Binance.AmountPrecision),
+			fmt.Sprint(r.Binance.PricePrecision),
+		}
+	}
+
+	return []string{
+		r.Market, r.BinanceSymbol, fmt.Sprint(r.Inverted), r.BinanceStatus, strings.Join(r.Permissions, " "),
+		binanceValues[0], r.Opendax.MinPrice.String(),
+		binanceValues[1], r.Opendax.MaxPrice.String(),
+		binanceValues[2], r.Opendax.MinAmount.String(), optionalDecimal(r.MinAmountDelta),
+		r.MinNotionalMultiplier.String(), r.MinNotionalFloor.String(),
+		binanceValues[3], r.Opendax.MaxAmount.String(),
+		binanceValues[4], fmt.Sprint(r.Opendax.AmountPrecision),
+		binanceValues[5], fmt.Sprint(r.Opendax.PricePrecision),
+		r.Diff.String(), r.Verdict, r.Error, strings.Join(r.Warnings, "; "), strings.Join(r.Unenforced, "; "),
+	}
+}
+
+func (r *MarketRecord) PrintText(w io.Writer) {
+	for _, warning := range r.Warnings {
+		color.New(color.FgYellow).Fprintln(w, "Inconsistent Binance filter", warning)
+	}
+
+	switch r.Verdict {
+	case VerdictError:
+		color.New(color.FgRed).Fprintf(w, "Error comparing %s: %s\n", r.Market, r.Error)
+	case VerdictMissing:
+		fmt.Fprintln(w, r.Market, "is missing on Binance as", r.BinanceSymbol)
+	case VerdictNotTrading:
+		color.New(color.FgYellow).Fprintf(w, "%s is not trading on Binance as %s, status: %s\n", r.Market, r.BinanceSymbol, r.BinanceStatus)
+	default:
+		fmt.Fprintln(w, "Comparing", r.Market)
+		fmt.Fprintln(w, "Equal:", r.Verdict == VerdictEqual)
+		fmt.Fprintf(w, "Min notional: x%s, floor %s %s\n", r.MinNotionalMultiplier, r.MinNotionalFloor, r.Opendax.QuoteUnit)
+		printMarketDiff(w, r.Diff)
+		for _, limit := range r.Unenforced {
+			fmt.Fprintln(w, "Binance limit not enforced:", limit)
+		}
+		fmt.Fprintln(w, "")
+	}
+}
+
+var severityColors = map[string]*color.Color{
+	opendax.SeverityCritical: color.New(color.FgRed),
+	opendax.SeverityWarning:  color.New(color.FgYellow),
+	opendax.SeverityInfo:     color.New(color.FgCyan),
+}
+
+// printMarketDiff prints the differing fields as a table, one colour per severity
+func printMarketDiff(w io.Writer, diff opendax.MarketDiff) {
+	if len(diff) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "%-18s %-20s %-20s %s\n", "FIELD", "OPENDAX", "BINANCE", "SEVERITY")
+	for _, fieldDiff := range diff {
+		severityColors[fieldDiff.Severity].Fprintf(w, "%-18s %-20s %-20s %s\n", fieldDiff.Field, fieldDiff.Old, fieldDiff.New, fieldDiff.Severity)
+	}
+	fmt.Fprintln(w, "")
+}
+
+// PriceSource is the Binance price the min amount is derived from: last, avg, vwap or mid
+var PriceSource = binance.PriceSourceLast
+
+// CompareFields is a comma separated list of the market fields compared with Binance, overriding the config
+var CompareFields = ""
+
+// compareFields returns the market fields to compare, from the flag or the market policy
+func compareFields(config *Config) ([]string, error) {
+	fields := config.MarketPolicy.Fields
+	if CompareFields != "" {
+		fields = strings.Split(CompareFields, ",")
+		for i, field := range fields {
+			fields[i] = strings.TrimSpace(field)
+		}
+	}
+
+	return fields, opendax.ValidateMarketFields(fields)
+}
+
+func compareMarkets() error {
+	config := readConfig()
+
+	if err := binance.ValidatePriceSource(PriceSource); err != nil {
+		return err
+	}
+
+	fields, err := compareFields(config)
+	if err != nil {
+		return err
+	}
+
+	renderer, err := newRenderer(OutputFormat, os.Stdout, (&MarketRecord{}).Columns())
+	if err != nil {
+		return err
+	}
+
+	binanceClient := newBinanceClient(config, "", "")
+	binanceInfo, err := binanceClient.ExchangeInfo()
+	if err != nil {
+		return err
+	}
+
+	opendaxClient := newAdminOpendaxClient(config)
+	opendaxMarkets, err := opendaxClient.FetchOpendaxMarkets()
+	if err != nil {
+		return err
+	}
+
+	summary := &marketsSummary{}
+	var banErr *binance.BanError
+
+	for _, opendaxMarket := range opendaxMarkets {
+		reference := config.Markets.For(opendaxMarket)
+		binanceMarket, ok := binanceInfo.MarketRegistry[reference.Symbol]
+		if !ok {
+			if err := renderer.Render(newMarketRecord(opendaxMarket, reference, nil, nil, nil, nil, fields, nil)); err != nil {
+				return err
+			}
+			summary.add(opendaxMarket.Name, OutcomeMissing)
+			continue
+		}
+
+		// Precisions of a halted or delisted market are not worth syncing
+		var convertedBinanceMarket, target *opendax.OpendaxMarket
+		var unenforced []string
+		var err error
+		if binanceMarket.IsTrading() {
+			convertedBinanceMarket, target, unenforced, err = binanceTargetMarket(binanceClient, binanceMarket, opendaxMarket, reference, config.MarketPolicy)
+		}
+
+		// Every remaining market would fail the same way, stop and keep what was updated so far
+		if errors.As(err, &banErr) {
+			break
+		}
+
+		record := newMarketRecord(opendaxMarket, reference, &binanceMarket, convertedBinanceMarket, target, unenforced, fields, err)
+		notional := config.MarketPolicy.MinNotional.For(opendaxMarket.Symbol, opendaxMarket.QuoteUnit)
+		record.MinNotionalMultiplier = notional.MultiplierOr(binance.DefaultMinNotionalMultiplier)
+		record.MinNotionalFloor = notional.FloorOrZero()
+		if err := renderer.Render(record); err != nil {
+			return err
+		}
+
+		if record.Verdict == VerdictError {
+			summary.add(opendaxMarket.Name, OutcomeFailed)
+			continue
+		}
+
+		if record.Verdict == VerdictEqual {
+			fmt.Fprintln(console(), "Skipping")
+			summary.add(opendaxMarket.Name, OutcomeEqual)
+			continue
+		}
+
+		question := "Update this market?"
+		var request opendax.UpdateMarketRequest
+		if record.Verdict == VerdictNotTrading {
+			if !DisableNotTrading || opendaxMarket.State == opendax.MarketStateDisabled {
+				summary.add(opendaxMarket.Name, OutcomeSkipped)
+				continue
+			}
+			question = "Disable this market?"
+			request = marketUpdateRequest(opendaxMarket, &opendaxMarket, fields)
+			request.State = opendax.MarketStateDisabled
+		} else {
+			request = marketUpdateRequest(opendaxMarket, target, fields)
+		}
+
+		var input string
+		if AutoEnabled {
+			fmt.Fprintln(console(), "Skipping market update prompt due to auto mode")
+		} else {
+			// Machine-readable records may be buffered or piped away, show the operator what they approve
+			if OutputFormat != "text" {
+				record.PrintText(console())
+			}
+			fmt.Fprint(console(), question)
+			fmt.Scanln(&input)
+		}
+
+		if AutoEnabled || input == "y" {
+			updatedMarket, err := opendaxClient.UpdateOpendaxMarket(request)
+			if err != nil {
+				fmt.Fprintf(console(), "Error updating %s: %s\n", opendaxMarket.Name, err)
+				summary.add(opendaxMarket.Name, OutcomeFailed)
+				continue
+			}
+
+			fmt.Fprintln(console(), "New market:")
+			updatedMarket.Fprint(console())
+
+			summary.add(opendaxMarket.Name, OutcomeUpdated)
+		} else {
+			if input != "n" {
+				fmt.Fprintf(console(), "Wrong input %q, expected y or n, skipping\n", input)
+			}
+			summary.add(opendaxMarket.Name, OutcomeSkipped)
+		}
+	}
+
+	if AutoEnabled {
+		finalizeMarketUpdates(opendaxClient, summary.markets(OutcomeUpdated))
+	}
+
+	if err := renderer.Close(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(console(), "Total OpenDAX markets:", len(opendaxMarkets))
+	summary.Fprint(console())
+
+	if banErr != nil {
+		return withBinanceHint(banErr)
+	}
+	return summary.Err()
+}
+
+// Outcomes of a market in a markets run
+const (
+	OutcomeSkipped = "skipped"
+	OutcomeEqual   = "equal"
+	OutcomeUpdated = "updated"
+	OutcomeFailed  = "failed"
+	OutcomeMissing = "missing"
+	OutcomeCreated = "created"
+)
+
+var outcomes = []string{OutcomeCreated, OutcomeUpdated, OutcomeEqual, OutcomeSkipped, OutcomeMissing, OutcomeFailed}
+
+// marketsSummary records what happened to every market of a run, so a failure does not hide the markets already updated
+type marketsSummary struct {
+	outcomes map[string][]string
+}
+
+func (s *marketsSummary) add(market, outcome string) {
+	if s.outcomes == nil {
+		s.outcomes = make(map[string][]string)
+	}
+	s.outcomes[outcome] = append(s.outcomes[outcome], market)
+}
+
+func (s *marketsSummary) markets(outcome string) []string {
+	return s.outcomes[outcome]
+}
+
+func (s *marketsSummary) Fprint(w io.Writer) {
+	fmt.Fprintln(w, "Summary:")
+	for _, outcome := range outcomes {
+		markets := s.markets(outcome)
+		if len(markets) == 0 {
+			// Only markets create reports created markets, the other commands leave the line out
+			if outcome == OutcomeCreated {
+				continue
+			}
+			fmt.Fprintf(w, "  %-8s 0\n", outcome)
+			continue
+		}
+		line := fmt.Sprintf("  %-8s %d %v\n", outcome, len(markets), markets)
+		if outcome == OutcomeFailed {
+			color.New(color.FgRed).Fprint(w, line)
+		} else {
+			fmt.Fprint(w, line)
+		}
+	}
+}
+
+// Err fails the run when a market failed, so the exit code tells scripts to look at the summary
+func (s *marketsSummary) Err() error {
+	if failed := s.markets(OutcomeFailed); len(failed) > 0 {
+		return fmt.Errorf("%d markets failed: %v", len(failed), failed)
+	}
+	return nil
+}
+
+// binanceTargetMarket converts the Binance reference market, and returns it along with the configuration the OpenDAX
+// market should have under the market policy and the Binance limits the policy left out
+func binanceTargetMarket(binanceClient *binance.BinanceClient, binanceMarket binance.BinanceMarket, opendaxMarket opendax.OpendaxMarket, reference mapping.Market, marketPolicy policy.MarketPolicy) (*opendax.OpendaxMarket, *opendax.OpendaxMarket, []string, error) {
+	notional := marketPolicy.MinNotional.For(opendaxMarket.Symbol, opendaxMarket.QuoteUnit)
+	convertedBinanceMarket, err := convertBinanceMarket(binanceClient, binanceMarket, reference.Inverted, notional)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// Renamed or wrapped assets keep the OpenDAX identity
+	convertedBinanceMarket.Symbol = opendaxMarket.Symbol
+	convertedBinanceMarket.Name = opendaxMarket.Name
+	convertedBinanceMarket.BaseUnit = opendaxMarket.BaseUnit
+	convertedBinanceMarket.QuoteUnit = opendaxMarket.QuoteUnit
+
+	target, unenforced := policyTargetMarket(opendaxMarket, convertedBinanceMarket, marketPolicy)
+
+	return convertedBinanceMarket, target, unenforced, nil
+}
+
+// policyTargetMarket applies the market policy to a copy of the converted market, which keeps the Binance values
+func policyTargetMarket(opendaxMarket opendax.OpendaxMarket, convertedBinanceMarket *opendax.OpendaxMarket, marketPolicy policy.MarketPolicy) (*opendax.OpendaxMarket, []string) {
+	target := *convertedBinanceMarket
+	unenforced := marketPolicy.Apply(opendaxMarket, &target)
+
+	return &target, unenforced
+}
+
+// convertBinanceMarket converts a Binance market into an OpenDAX market at the current ticker price,
+// with a min amount covering the min notional of the policy
+func convertBinanceMarket(binanceClient *binance.BinanceClient, binanceMarket binance.BinanceMarket, inverted bool, notional policy.NotionalPolicy) (*opendax.OpendaxMarket, error) {
+	price, err := binanceClient.Price(PriceSource, binanceMarket.Symbol)
+	if err != nil {
+		return nil, fmt.Errorf("%s price fetch for %s: %w", PriceSource, binanceMarket.Symbol, err)
+	}
+
+	// Pairs without trades or with an empty book have a zero price, no min amount can be derived from it
+	if !price.IsPositive() {
+		return nil, fmt.Errorf("%s price of %s is %s, not positive", PriceSource, binanceMarket.Symbol, price)
+	}
+
+	if inverted {
+		binanceMarket = binanceMarket.Invert(price)
+		price = decimal.NewFromInt(1).Div(price)
+	}
+
+	minAmount := binanceMarket.CalculateMinAmount(price, notional.MultiplierOr(binance.DefaultMinNotionalMultiplier), notional.FloorOrZero())
+	if minAmount.Equal(decimal.Zero) {
+		return nil, fmt.Errorf("min amount of %s is zero", binanceMarket.Symbol)
+	}
+
+	convertedBinanceMarket, err := binanceMarket.ToOpendaxMarket(minAmount)
+	if err != nil {
+		return nil, fmt.Errorf("conversion of %s: %w", binanceMarket.Symbol, err)
+	}
+
+	// Prices beyond it would be rejected by Binance, so round it down
+	maxPrice := binanceMarket.MaxPrice(price)
+	convertedBinanceMarket.MaxPrice = maxPrice.Truncate(int32(convertedBinanceMarket.PricePrecision))
+
+	return convertedBinanceMarket, nil
+}
+
+// marketUpdateRequest updates the compared fields of the market to the target, and sends the other fields unchanged
+func marketUpdateRequest(opendaxMarket opendax.OpendaxMarket, target *opendax.OpendaxMarket, fields []string) opendax.UpdateMarketRequest {
+	merged := opendax.MergeOpendaxMarkets(&opendaxMarket, target, fields)
+
+	return opendax.UpdateMarketRequest{
+		Symbol:          opendaxMarket.Symbol,
+		MinPrice:        merged.MinPrice,
+		MaxPrice:        merged.MaxPrice,
+		MinAmount:       merged.MinAmount,
+		MaxAmount:       optionalMaxAmount(&merged),
+		AmountPrecision: merged.AmountPrecision,
+		PricePrecision:  merged.PricePrecision,
+	}
+}
+
+// optionalMaxAmount leaves the max amount out of requests unless there is one, Peatio versions without it reject the param
+func optionalMaxAmount(target *opendax.OpendaxMarket) *decimal.Decimal {
+	if target.MaxAmount.IsZero() {
+		return nil
+	}
+	return &target.MaxAmount
+}
+
+// finalizeMarketUpdates saves the list of updated markets and restarts Finex so it picks up the new configuration
+func finalizeMarketUpdates(opendaxClient *opendax.OpendaxClient, updatedMarkets []string) {
+	if len(updatedMarkets) == 0 {
+		return
+	}
+
+	if DryRunEnabled {
+		fmt.Fprintf(console(), "Dry run, markets that would be updated: %v\n", updatedMarkets)
+	} else {
+		err := helpers.WriteToFile("updated-markets.txt", fmt.Sprintf("%v", updatedMarkets))
+		if err != nil {
+			fmt.Fprintf(console(), "Error saving updated markets: %s\nUpdated markets: %v", err, updatedMarkets)
+		}
+	}
+
+	restartFinex(opendaxClient)
+}
+
+// restartFinex bumps the Finex restart secret, so it reloads the markets configuration
+func restartFinex(opendaxClient *opendax.OpendaxClient) {
+	secretUpdateParams := opendax.UpdateSecretRequest{
+		Scope: "private",
+		Key:   "restart",
+		Value: fmt.Sprint(time.Now()),
+	}
+
+	err := opendaxClient.UpdateOpendaxSecret(secretUpdateParams)
+
+	if err != nil {
+		fmt.Fprintf(console(), "Error updating Finex restart secret: %s", err)
+	}
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/openware/binance-cli/pkg/binance"
+	"github.com/openware/binance-cli/pkg/helpers"
+	"github.com/openware/binance-cli/pkg/mapping"
+	"github.com/openware/binance-cli/pkg/opendax"
+	"github.com/openware/binance-cli/pkg/policy"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarketUpdateRequestFields(t *testing.T) {
+	target := plannedMarket
+	target.MinPrice = decimal.RequireFromString("0.1")
+	target.MaxPrice = decimal.RequireFromString("50000")
+	target.MinAmount = decimal.RequireFromString("0.006")
+	target.MaxAmount = decimal.RequireFromString("5000")
+	target.AmountPrecision = 3
+	target.PricePrecision = 1
+
+	t.Run("excluded fields are sent unchanged", func(t *testing.T) {
+		request := marketUpdateRequest(plannedMarket, &target, []string{"min_amount"})
+
+		assert.Equal(t, "ethusdt", request.Symbol)
+		assert.Equal(t, "0.006", request.MinAmount.String())
+		assert.Equal(t, "0.01", request.MinPrice.String())
+		assert.Equal(t, "100000", request.MaxPrice.String())
+		assert.Equal(t, "9000", request.MaxAmount.String())
+		assert.Equal(t, int64(4), request.AmountPrecision)
+		assert.Equal(t, int64(2), request.PricePrecision)
+	})
+
+	t.Run("every field by default", func(t *testing.T) {
+		request := marketUpdateRequest(plannedMarket, &target, nil)
+
+		assert.Equal(t, opendax.UpdateMarketRequest{
+			Symbol:          "ethusdt",
+			MinPrice:        target.MinPrice,
+			MaxPrice:        target.MaxPrice,
+			MinAmount:       target.MinAmount,
+			MaxAmount:       &target.MaxAmount,
+			AmountPrecision: 3,
+			PricePrecision:  1,
+		}, request)
+	})
+}
+
+func TestMarketRecordBinanceValues(t *testing.T) {
+	converted := plannedMarket
+	converted.MaxPrice = decimal.RequireFromString("50000")
+	converted.MaxAmount = decimal.RequireFromString("5000")
+
+	target, unenforced := policyTargetMarket(plannedMarket, &converted, policy.MarketPolicy{})
+	assert.Len(t, unenforced, 2)
+
+	binanceMarket := &binance.BinanceMarket{Status: "TRADING"}
+	record := newMarketRecord(plannedMarket, mapping.Market{Symbol: "ETHUSDT"}, binanceMarket, &converted, target, unenforced, nil, nil)
+
+	// Limits left out by the policy are still reported with their Binance values
+	assert.Equal(t, "50000", record.Binance.MaxPrice.String())
+	assert.Equal(t, "5000", record.Binance.MaxAmount.String())
+	assert.Empty(t, record.Diff)
+	assert.Equal(t, VerdictEqual, record.Verdict)
+
+	request := marketUpdateRequest(plannedMarket, target, nil)
+	assert.Equal(t, "100000", request.MaxPrice.String())
+	assert.Equal(t, "9000", request.MaxAmount.String())
+}
+
+func TestMarketRecordMinAmountTolerance(t *testing.T) {
+	tolerance := decimal.RequireFromString("20")
+	converted := plannedMarket
+	converted.MinAmount = decimal.RequireFromString("0.0055")
+
+	target, unenforced := policyTargetMarket(plannedMarket, &converted, policy.MarketPolicy{MinAmountTolerance: &tolerance, EnforceMaxPrice: true, EnforceMaxAmount: true})
+	assert.Len(t, unenforced, 1)
+	assert.Equal(t, "0.005", target.MinAmount.String())
+
+	binanceMarket := &binance.BinanceMarket{Status: "TRADING"}
+	record := newMarketRecord(plannedMarket, mapping.Market{Symbol: "ETHUSDT"}, binanceMarket, &converted, target, unenforced, nil, nil)
+
+	// The drift within the band is reported, it only does not trigger an update
+	assert.Equal(t, "0.0055", record.Binance.MinAmount.String())
+	assert.Equal(t, "-0.0005", record.MinAmountDelta.String())
+	assert.Empty(t, record.Diff)
+	assert.Equal(t, VerdictEqual, record.Verdict)
+}
+
+func mixedSummary() *marketsSummary {
+	summary := &marketsSummary{}
+	summary.add("ETH/USDT", OutcomeUpdated)
+	summary.add("BTC/USDT", OutcomeFailed)
+	summary.add("XRP/USDT", OutcomeSkipped)
+	summary.add("XYZ/USDT", OutcomeMissing)
+	summary.add("BNB/USDT", OutcomeEqual)
+	summary.add("ADA/USDT", OutcomeUpdated)
+	return summary
+}
+
+func TestMarketsSummary(t *testing.T) {
+	summary := mixedSummary()
+
+	assert.Equal(t, []string{"ETH/USDT", "ADA/USDT"}, summary.markets(OutcomeUpdated))
+	assert.Equal(t, []string{"BTC/USDT"}, summary.markets(OutcomeFailed))
+	assert.Empty(t, summary.markets(OutcomeCreated))
+
+	out := &bytes.Buffer{}
+	summary.Fprint(out)
+	assert.Equal(t, "Summary:\n"+
+		"  updated  2 [ETH/USDT ADA/USDT]\n"+
+		"  equal    1 [BNB/USDT]\n"+
+		"  skipped  1 [XRP/USDT]\n"+
+		"  missing  1 [XYZ/USDT]\n"+
+		"  failed   1 [BTC/USDT]\n", out.String())
+
+	assert.EqualError(t, summary.Err(), "1 markets failed: [BTC/USDT]")
+
+	// Skipped, missing and equal markets do not fail the run
+	passing := &marketsSummary{}
+	passing.add("XRP/USDT", OutcomeSkipped)
+	passing.add("XYZ/USDT", OutcomeMissing)
+	passing.add("BNB/USDT", OutcomeEqual)
+	assert.NoError(t, passing.Err())
+	assert.NoError(t, (&marketsSummary{}).Err())
+}
+
+func TestFinalizeUpdatedMarkets(t *testing.T) {
+	restarts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		restarts++
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	client := opendax.NewOpendaxClient(server.URL)
+	client.SetRetryPolicy(helpers.RetryPolicy{})
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer os.Chdir(wd)
+
+	// Only updated markets are listed for the Finex restart
+	finalizeMarketUpdates(client, mixedSummary().markets(OutcomeUpdated))
+	updated, err := os.ReadFile("updated-markets.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "[ETH/USDT ADA/USDT]", string(updated))
+	assert.Equal(t, 1, restarts)
+
+	// Nothing to restart when no market was updated
+	finalizeMarketUpdates(client, (&marketsSummary{}).markets(OutcomeUpdated))
+	assert.Equal(t, 1, restarts)
+}
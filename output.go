@@ -0,0 +1,145 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+const (
+	VerdictOk         = "ok"
+	VerdictTooLow     = "too_low"
+	VerdictTooHigh    = "too_high"
+	VerdictMissing    = "missing"
+	VerdictEqual      = "equal"
+	VerdictDifferent  = "different"
+	VerdictError      = "error"
+	VerdictNotTrading = "not_trading"
+)
+
+// OutputFormat defines how fees and markets comparison records are rendered
+var OutputFormat = "text"
+
+// Record is a single comparison result emitted by the fees and markets commands
+type Record interface {
+	// Columns returns the CSV header of the record type
+	Columns() []string
+	// Values returns the CSV row of the record, in the Columns order
+	Values() []string
+	// PrintText prints the record for a human reader
+	PrintText(w io.Writer)
+}
+
+// Renderer writes records to the output in one of the supported formats
+type Renderer interface {
+	Render(record Record) error
+	Close() error
+}
+
+// newRenderer returns the renderer of the format, columns are the CSV header written even when no record is rendered
+func newRenderer(format string, w io.Writer, columns []string) (Renderer, error) {
+	switch format {
+	case "text":
+		return &textRenderer{w: w}, nil
+	case "json":
+		return &jsonRenderer{w: w, records: []Record{}}, nil
+	case "ndjson":
+		return &ndjsonRenderer{encoder: json.NewEncoder(w)}, nil
+	case "csv":
+		return &csvRenderer{w: csv.NewWriter(w), columns: columns}, nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q, expected text, json, ndjson or csv", format)
+	}
+}
+
+// console returns the writer for human oriented messages, which must stay out of machine-readable output
+func console() io.Writer {
+	if OutputFormat == "text" {
+		return os.Stdout
+	}
+	return os.Stderr
+}
+
+type textRenderer struct {
+	w io.Writer
+}
+
+func (r *textRenderer) Render(record Record) error {
+	record.PrintText(r.w)
+	return nil
+}
+
+func (r *textRenderer) Close() error {
+	return nil
+}
+
+type jsonRenderer struct {
+	w       io.Writer
+	records []Record
+}
+
+func (r *jsonRenderer) Render(record Record) error {
+	r.records = append(r.records, record)
+	return nil
+}
+
+func (r *jsonRenderer) Close() error {
+	encoder := json.NewEncoder(r.w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r.records)
+}
+
+type ndjsonRenderer struct {
+	encoder *json.Encoder
+}
+
+func (r *ndjsonRenderer) Render(record Record) error {
+	return r.encoder.Encode(record)
+}
+
+func (r *ndjsonRenderer) Close() error {
+	return nil
+}
+
+type csvRenderer struct {
+	w             *csv.Writer
+	columns       []string
+	headerWritten bool
+}
+
+func (r *csvRenderer) writeHeader() error {
+	r.headerWritten = true
+	return r.w.Write(r.columns)
+}
+
+func (r *csvRenderer) Render(record Record) error {
+	if !r.headerWritten {
+		if r.columns == nil {
+			r.columns = record.Columns()
+		}
+		if err := r.writeHeader(); err != nil {
+			return err
+		}
+	}
+
+	if err := r.w.Write(record.Values()); err != nil {
+		return err
+	}
+
+	// Flush every row, so interactive runs show progress
+	r.w.Flush()
+	return r.w.Error()
+}
+
+func (r *csvRenderer) Close() error {
+	// An empty run still gets a header, so consumers can tell it from a failed one
+	if !r.headerWritten && r.columns != nil {
+		if err := r.writeHeader(); err != nil {
+			return err
+		}
+	}
+	r.w.Flush()
+	return r.w.Error()
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/openware/binance-cli/pkg/binance"
+	"github.com/openware/binance-cli/pkg/opendax"
+	"github.com/openware/binance-cli/pkg/policy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func feeRecordsFixture() []Record {
+	eth := &opendax.OpendaxCurrency{Code: "eth", WithdrawFee: mustDecimal("0.001"), MinWithdrawAmount: mustDecimal("0.01")}
+	network := &binance.Network{Name: "ETH", WithdrawFee: mustDecimal("0.005"), WithdrawMin: mustDecimal("0.01")}
+	xyz := &opendax.OpendaxCurrency{Code: "xyz", WithdrawFee: mustDecimal("1"), MinWithdrawAmount: mustDecimal("2")}
+
+	return []Record{
+		newFeeRecord(eth, "ETH", network, policy.FeePolicy{}),
+		newFeeRecord(xyz, "XYZ", nil, policy.FeePolicy{}),
+	}
+}
+
+func render(t *testing.T, format string, records []Record) string {
+	out := &bytes.Buffer{}
+	renderer, err := newRenderer(format, out, (&FeeRecord{}).Columns())
+	require.NoError(t, err)
+
+	for _, record := range records {
+		require.NoError(t, renderer.Render(record))
+	}
+	require.NoError(t, renderer.Close())
+
+	return out.String()
+}
+
+func TestCSVRenderer(t *testing.T) {
+	rows, err := csv.NewReader(strings.NewReader(render(t, "csv", feeRecordsFixture()))).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+
+	columns := (&FeeRecord{}).Columns()
+	assert.Equal(t, columns, rows[0])
+	assert.Equal(t, "currency", columns[0])
+	assert.Equal(t, "verdict", columns[len(columns)-1])
+
+	row := make(map[string]string)
+	for i, column := range rows[0] {
+		row[column] = rows[1][i]
+	}
+	assert.Equal(t, "eth", row["currency"])
+	assert.Equal(t, "ETH", row["network"])
+	assert.Equal(t, "0.005", row["target_withdraw_fee"])
+	assert.Equal(t, "0.001", row["opendax_withdraw_fee"])
+	assert.Equal(t, "-0.004", row["withdraw_fee_delta"])
+	assert.Equal(t, VerdictTooLow, row["verdict"])
+
+	// Values left out for a missing coin keep the columns aligned
+	assert.Len(t, rows[2], len(columns))
+	assert.Equal(t, "xyz", rows[2][0])
+	assert.Equal(t, "", rows[2][3])
+	assert.Equal(t, VerdictMissing, rows[2][len(columns)-1])
+}
+
+func TestJSONRenderer(t *testing.T) {
+	var records []map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(render(t, "json", feeRecordsFixture())), &records))
+	require.Len(t, records, 2)
+	assert.Equal(t, "eth", records[0]["currency"])
+	assert.Equal(t, "0.005", records[0]["target_withdraw_fee"])
+	assert.Equal(t, "xyz", records[1]["currency"])
+	assert.Nil(t, records[1]["target_withdraw_fee"])
+}
+
+func TestNDJSONRenderer(t *testing.T) {
+	lines := strings.Split(strings.TrimSuffix(render(t, "ndjson", feeRecordsFixture()), "\n"), "\n")
+	require.Len(t, lines, 2)
+
+	for i, currency := range []string{"eth", "xyz"} {
+		var record map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(lines[i]), &record), lines[i])
+		assert.Equal(t, currency, record["currency"])
+	}
+}
+
+func TestRenderersEmptyRun(t *testing.T) {
+	assert.Equal(t, strings.Join((&FeeRecord{}).Columns(), ",")+"\n", render(t, "csv", nil))
+	assert.Equal(t, "[]\n", render(t, "json", nil))
+	assert.Equal(t, "", render(t, "ndjson", nil))
+	assert.Equal(t, "", render(t, "text", nil))
+}
+
+func TestTextRenderer(t *testing.T) {
+	out := render(t, "text", feeRecordsFixture())
+	assert.Contains(t, out, "ETH coin on ETH network:")
+	assert.Contains(t, out, "XYZ cannot be found on Binance")
+}
+
+func TestUnknownOutputFormat(t *testing.T) {
+	_, err := newRenderer("xml", &bytes.Buffer{}, nil)
+	assert.EqualError(t, err, `unknown output format "xml", expected text, json, ndjson or csv`)
+}
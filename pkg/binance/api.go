@@ -7,7 +7,11 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"os"
 	"time"
+
+	"github.com/openware/binance-cli/pkg/helpers"
 )
 
 const (
@@ -15,66 +19,138 @@ const (
 	coinsInfoEndpoint       = "/sapi/v1/capital/config/getall"
 	exchangeInfoEndpoint    = "/api/v3/exchangeInfo"
 	tickerPriceInfoEndpoint = "/api/v3/ticker/price"
-	NotFoundError           = "404 Record Not Found"
-	ServiceUnavailableError = "503 Service Unavailable"
-	HttpTransportError      = "HTTP Transport Error"
+	avgPriceEndpoint        = "/api/v3/avgPrice"
+	ticker24hrEndpoint      = "/api/v3/ticker/24hr"
+	bookTickerEndpoint      = "/api/v3/ticker/bookTicker"
+	serverTimeEndpoint      = "/api/v3/time"
+	HttpTransportError      = helpers.HttpTransportError
+	TooManyRequestsError    = "429 Too Many Requests"
+	IpBannedError           = "418 IP banned by Binance"
 )
 
-var SignedEndpoints = map[string]struct{}{
-	coinsInfoEndpoint: {},
+func (bc *BinanceClient) apiCall(request *Request, receiver interface{}) (interface{}, error) {
+	resp, err := bc.send(request)
+	if err != nil {
+		return receiver, err
+	}
+	defer resp.Body.Close()
+
+	err = json.NewDecoder(resp.Body).Decode(receiver)
+	return receiver, err
 }
 
-func (bc *BinanceClient) apiCall(endpoint string, receiver interface{}) (interface{}, error) {
-	uri := bc.url + endpoint
-	req, _ := http.NewRequest("GET", uri, nil)
+// send sends the request once the request weight allows it, and sends it again after the delay asked on 429,
+// or after a backoff on transport and server errors, unless the request is not retryable.
+// Non-2xx responses are returned as *HTTPError.
+func (bc *BinanceClient) send(request *Request) (*http.Response, error) {
+	endpoint := request.String()
+	retries := 0
+
+	for attempt := 1; ; attempt++ {
+		if err := bc.limiter.wait(); err != nil {
+			return nil, err
+		}
+
+		req, err := http.NewRequest(request.Method, bc.url+request.Endpoint, nil)
+		if err != nil {
+			return nil, err
+		}
 
-	fmt.Printf("Calling %s\n", uri)
+		fmt.Fprintf(os.Stderr, "Calling %s %s\n", request.Method, bc.url+endpoint)
 
-	req.Header.Add("X-MBX-APIKEY", bc.apiKey)
+		if request.Security.NeedsApiKey() {
+			req.Header.Add("X-MBX-APIKEY", bc.apiKey)
+		}
+
+		// Signed requests get a fresh timestamp on every attempt
+		req.URL.RawQuery = request.Params.Encode()
+		if request.Security.NeedsSignature() {
+			q, err := bc.signedQuery(request.Params)
+			if err != nil {
+				return nil, err
+			}
+			req.URL.RawQuery = q
+		}
 
-	if _, ok := SignedEndpoints[endpoint]; ok {
-		q, err := bc.mandatoryBinanceParameters()
+		resp, err := http.DefaultClient.Do(req)
 		if err != nil {
-			return receiver, err
+			if request.Retryable() && retries < bc.retry.MaxRetries {
+				retries++
+				bc.backoff(retries, err)
+				continue
+			}
+			return nil, &TransportError{Endpoint: endpoint, Err: err}
 		}
-		req.URL.RawQuery = q
-	}
 
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
+		bc.limiter.update(resp.Header)
 
-	if resp.StatusCode == http.StatusNotFound {
-		return receiver, fmt.Errorf(NotFoundError)
-	}
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return resp, nil
+		}
 
-	if resp.StatusCode == http.StatusServiceUnavailable || resp.StatusCode == http.StatusBadGateway {
-		return receiver, fmt.Errorf(ServiceUnavailableError)
+		body, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		httpErr := newHTTPError(resp.StatusCode, endpoint, body)
+
+		switch {
+		case resp.StatusCode == http.StatusTeapot:
+			bc.limiter.ban(retryAfter(resp.Header, time.Hour))
+			return nil, &BanError{Until: bc.limiter.bannedUntil, Err: httpErr}
+		case resp.StatusCode == http.StatusTooManyRequests:
+			delay := retryAfter(resp.Header, time.Minute)
+			if !request.Retryable() || attempt > maxRateLimitRetries {
+				return nil, fmt.Errorf("%s, retry after %s: %w", TooManyRequestsError, delay, httpErr)
+			}
+			fmt.Fprintf(os.Stderr, "%s, retrying after %s\n", TooManyRequestsError, delay)
+			bc.limiter.pause(delay)
+		case request.Retryable() && helpers.RetryableStatus(req.Method, resp.StatusCode) && retries < bc.retry.MaxRetries:
+			retries++
+			bc.backoff(retries, httpErr)
+		default:
+			return nil, httpErr
+		}
 	}
+}
 
-	err = json.NewDecoder(resp.Body).Decode(receiver)
-
-	re, _ := ioutil.ReadAll(resp.Body)
-	fmt.Println(string(re))
-	return receiver, err
+// backoff waits before sending a failed request again
+func (bc *BinanceClient) backoff(retry int, err error) {
+	delay := bc.retry.Backoff(retry)
+	fmt.Fprintf(os.Stderr, "%s, retry %d/%d in %s\n", err, retry, bc.retry.MaxRetries, delay.Round(time.Millisecond))
+	bc.limiter.pause(delay)
 }
 
-func (bc *BinanceClient) mandatoryBinanceParameters() (string, error) {
-	timestampParam := fmt.Sprintf("timestamp=%v", currentTimestamp())
+// signedQuery adds the timestamp and recvWindow to the parameters, and signs the whole encoded query
+func (bc *BinanceClient) signedQuery(requestParams url.Values) (string, error) {
+	if !bc.timeSynced {
+		if err := bc.SyncTime(); err != nil {
+			return "", err
+		}
+	}
+
+	params := url.Values{}
+	for key, values := range requestParams {
+		params[key] = values
+	}
+	params.Set("timestamp", fmt.Sprint(bc.serverTimestamp()))
+	if bc.recvWindow > 0 {
+		params.Set("recvWindow", fmt.Sprint(bc.recvWindow))
+	}
+
+	// The same encoded query is signed and sent
+	query := params.Encode()
 	mac := hmac.New(sha256.New, []byte(bc.secret))
-	_, err := mac.Write([]byte(timestampParam))
+	_, err := mac.Write([]byte(query))
 	if err != nil {
 		return "", err
 	}
 
 	signature := fmt.Sprintf("%x", mac.Sum(nil))
-	return fmt.Sprintf("timestamp=%v&signature=%s", currentTimestamp(), signature), nil
+	return fmt.Sprintf("%s&signature=%s", query, signature), nil
 }
 
-func currentTimestamp() int64 {
-	return FormatTimestamp(time.Now())
+// serverTimestamp returns the current Binance server time, from the local clock and the offset measured by SyncTime
+func (bc *BinanceClient) serverTimestamp() int64 {
+	return FormatTimestamp(time.Now().Add(bc.timeOffset))
 }
 
 // FormatTimestamp formats a time into Unix timestamp in milliseconds, as requested by Binance.
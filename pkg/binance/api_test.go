@@ -1,11 +1,17 @@
 package binance
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/require"
@@ -30,15 +36,21 @@ var (
 		{
 			Type:        "LOT_SIZE",
 			MinQuantity: decimal.RequireFromString("0.00010000"),
+			MaxQuantity: decimal.RequireFromString("9000.00000000"),
+			StepSize:    decimal.RequireFromString("0.00010000"),
 		},
 		{
-			Type:        "MIN_NOTIONAL",
-			MinNotional: decimal.RequireFromString("10.00000000"),
+			Type:          "MIN_NOTIONAL",
+			MinNotional:   decimal.RequireFromString("10.00000000"),
+			ApplyToMarket: true,
+			AvgPriceMins:  5,
 		},
 	}
 
 	expectedMarket = BinanceMarket{
 		Symbol:         "ETHUSDT",
+		Status:         "TRADING",
+		Permissions:    []string{"SPOT", "MARGIN"},
 		BaseUnit:       "ETH",
 		QuoteUnit:      "USDT",
 		QuotePrecision: decimal.RequireFromString("8"),
@@ -105,6 +117,12 @@ func TestExchangeInfoEndpoint(t *testing.T) {
 	assert.NilError(t, err)
 
 	expectedExchangeInfoRes := &BinanceExchangeInfo{
+		RateLimits: []RateLimit{
+			{RateLimitType: "REQUEST_WEIGHT", Interval: "MINUTE", IntervalNum: 1, Limit: 1200},
+			{RateLimitType: "ORDERS", Interval: "SECOND", IntervalNum: 10, Limit: 50},
+			{RateLimitType: "ORDERS", Interval: "DAY", IntervalNum: 1, Limit: 160000},
+			{RateLimitType: "RAW_REQUESTS", Interval: "MINUTE", IntervalNum: 5, Limit: 6100},
+		},
 		Symbols:        []BinanceMarket{expectedMarket},
 		MarketRegistry: map[string]BinanceMarket{"ETHUSDT": expectedMarket},
 	}
@@ -118,7 +136,7 @@ func TestCalculateMinAmount(t *testing.T) {
 		Price:  decimal.RequireFromString("3500.00000000"),
 	}
 
-	minAmount := expectedMarket.CalculateMinAmount(expectedTickerPriceRes.Price)
+	minAmount := expectedMarket.CalculateMinAmount(expectedTickerPriceRes.Price, DefaultMinNotionalMultiplier, decimal.Zero)
 
 	assert.DeepEqual(t, decimal.RequireFromString("0.003"), minAmount)
 }
@@ -132,6 +150,7 @@ func TestToOpendaxMarket(t *testing.T) {
 		MinPrice:        expectedFilters[0].MinPrice,
 		MaxPrice:        decimal.RequireFromString("0.00"),
 		MinAmount:       decimal.RequireFromString("0.0030"),
+		MaxAmount:       expectedFilters[1].MaxQuantity,
 		AmountPrecision: int64(4),
 		PricePrecision:  int64(2),
 	}
@@ -140,3 +159,144 @@ func TestToOpendaxMarket(t *testing.T) {
 	require.NoError(t, err)
 	assert.DeepEqual(t, expectedOpendaxMarket, res)
 }
+
+func TestCalculateMinAmountPolicy(t *testing.T) {
+	price := decimal.RequireFromString("3500")
+
+	t.Run("multiplier", func(t *testing.T) {
+		minAmount := expectedMarket.CalculateMinAmount(price, decimal.RequireFromString("1.4"), decimal.Zero)
+		assert.Equal(t, "0.004", minAmount.String())
+	})
+
+	t.Run("floor in quote unit", func(t *testing.T) {
+		minAmount := expectedMarket.CalculateMinAmount(price, DefaultMinNotionalMultiplier, decimal.RequireFromString("35"))
+		assert.Equal(t, "0.01", minAmount.String())
+	})
+
+	t.Run("zero price", func(t *testing.T) {
+		assert.Equal(t, true, expectedMarket.CalculateMinAmount(decimal.Zero, DefaultMinNotionalMultiplier, decimal.Zero).IsZero())
+	})
+}
+
+func TestTickerPricesEndpoint(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	calls := 0
+	mux.HandleFunc(tickerPriceInfoEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, fixture("ticker_prices.json"))
+	})
+
+	res, err := binanceClient.TickerPrices()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, BinanceTickerPrices{
+		{Symbol: "ETHBTC", Price: decimal.RequireFromString("0.05710000")},
+		{Symbol: "ETHUSDT", Price: decimal.RequireFromString("3500.00000000")},
+	}, res)
+
+	t.Run("prices are cached for the run", func(t *testing.T) {
+		calls = 0
+		client := NewBinanceClient("", "", server.URL)
+
+		price, err := client.TickerPrice("ETHUSDT")
+		assert.NilError(t, err)
+		assert.Equal(t, "3500", price.String())
+
+		price, err = client.TickerPrice("ETHBTC")
+		assert.NilError(t, err)
+		assert.Equal(t, "0.0571", price.String())
+
+		_, err = client.TickerPrice("BNBBTC")
+		assert.Error(t, err, "no last price for BNBBTC")
+		assert.Equal(t, 1, calls)
+	})
+}
+
+func TestTickerPricesForEndpoint(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tickerPriceInfoEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, `["ETHBTC","ETHUSDT"]`, r.URL.Query().Get("symbols"))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, fixture("ticker_prices.json"))
+	})
+
+	res, err := binanceClient.TickerPricesFor([]string{"ETHBTC", "ETHUSDT"})
+	assert.NilError(t, err)
+	assert.Equal(t, 2, len(res))
+}
+
+func TestPriceSources(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	fixtures := map[string]string{
+		tickerPriceInfoEndpoint: "ticker_prices.json",
+		avgPriceEndpoint:        "avg_price_ethusdt.json",
+		ticker24hrEndpoint:      "ticker_24hr.json",
+		bookTickerEndpoint:      "book_ticker.json",
+	}
+	for endpoint, path := range fixtures {
+		path := path
+		mux.HandleFunc(endpoint, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, fixture(path))
+		})
+	}
+
+	for source, expected := range map[string]string{
+		PriceSourceLast: "3500",
+		PriceSourceAvg:  "3490.5",
+		PriceSourceVwap: "3480.12",
+		PriceSourceMid:  "3500",
+	} {
+		price, err := binanceClient.Price(source, "ETHUSDT")
+		assert.NilError(t, err, source)
+		assert.Equal(t, expected, price.String(), source)
+	}
+
+	_, err := binanceClient.Price(PriceSourceMid, "XYZUSDT")
+	assert.Error(t, err, "no mid price for XYZUSDT")
+
+	_, err = binanceClient.Price("close", "ETHUSDT")
+	assert.Error(t, err, `unknown price source "close", expected one of last, avg, vwap, mid`)
+}
+
+func TestSignedRequestTimestamp(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	serverTime := time.Now().Add(10 * time.Second)
+	mux.HandleFunc(serverTimeEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"serverTime":%d}`, FormatTimestamp(serverTime))
+	})
+
+	var query url.Values
+	var signedQuery string
+	mux.HandleFunc(coinsInfoEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		signedQuery = strings.Split(r.URL.RawQuery, "&signature=")[0]
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `[]`)
+	})
+
+	binanceClient.SetRecvWindow(10000)
+	_, err := binanceClient.CoinsInfo()
+	assert.NilError(t, err)
+
+	timestamp, err := strconv.ParseInt(query.Get("timestamp"), 10, 64)
+	assert.NilError(t, err)
+	assert.Assert(t, timestamp >= FormatTimestamp(serverTime)-1000 && timestamp <= FormatTimestamp(serverTime)+1000)
+	assert.Equal(t, "10000", query.Get("recvWindow"))
+
+	mac := hmac.New(sha256.New, []byte(binanceClient.secret))
+	mac.Write([]byte(signedQuery))
+	assert.Equal(t, fmt.Sprintf("%x", mac.Sum(nil)), query.Get("signature"))
+}
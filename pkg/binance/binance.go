@@ -1,30 +1,189 @@
 package binance
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/openware/binance-cli/pkg/helpers"
+	"github.com/shopspring/decimal"
+)
 
 func NewBinanceClient(apiKey, secret, url string) *BinanceClient {
 	return &BinanceClient{
 		apiKey: apiKey,
 		secret: secret,
 		url:    url,
+		retry:  helpers.DefaultRetryPolicy,
 	}
 }
 
+// SetRecvWindow defines how many milliseconds after their timestamp signed requests are valid, 0 for the Binance default
+func (bc *BinanceClient) SetRecvWindow(recvWindow int64) {
+	bc.recvWindow = recvWindow
+}
+
+// ServerTime fetches the Binance server time in milliseconds
+func (bc *BinanceClient) ServerTime() (*BinanceServerTime, error) {
+	serverTime := &BinanceServerTime{}
+	_, err := bc.apiCall(NewPublicRequest(serverTimeEndpoint), &serverTime)
+	return serverTime, err
+}
+
+// SyncTime measures the offset of the local clock to the Binance server time, signed requests are timestamped with it
+func (bc *BinanceClient) SyncTime() error {
+	sentAt := time.Now()
+	serverTime, err := bc.ServerTime()
+	if err != nil {
+		return err
+	}
+	receivedAt := time.Now()
+
+	// The server time was read about halfway through the round trip
+	localTime := sentAt.Add(receivedAt.Sub(sentAt) / 2)
+	bc.timeOffset = time.Unix(0, serverTime.ServerTime*int64(time.Millisecond)).Sub(localTime)
+	bc.timeSynced = true
+
+	return nil
+}
+
+// SetRetryPolicy defines how requests failing with a transport or server error are sent again
+func (bc *BinanceClient) SetRetryPolicy(policy helpers.RetryPolicy) {
+	bc.retry = policy
+}
+
 func (bc *BinanceClient) CoinsInfo() (BinanceCurrencies, error) {
 	currencies := BinanceCurrencies{}
-	_, err := bc.apiCall(coinsInfoEndpoint, &currencies)
+	_, err := bc.apiCall(NewRequest(http.MethodGet, coinsInfoEndpoint, SecurityUserData), &currencies)
 	return currencies, err
 }
 
 func (bc *BinanceClient) ExchangeInfo() (*BinanceExchangeInfo, error) {
 	exchangeInfo := &BinanceExchangeInfo{}
-	_, err := bc.apiCall(exchangeInfoEndpoint, &exchangeInfo)
+	_, err := bc.apiCall(NewPublicRequest(exchangeInfoEndpoint), &exchangeInfo)
 	exchangeInfo.FillRegistry()
+	bc.limiter.setLimits(exchangeInfo.RateLimits)
 	return exchangeInfo, err
 }
 
 func (bc *BinanceClient) TickerPriceInfo(symbol string) (*BinanceTickerPrice, error) {
 	tickerPrice := &BinanceTickerPrice{}
-	_, err := bc.apiCall(fmt.Sprintf("%s?symbol=%s", tickerPriceInfoEndpoint, symbol), &tickerPrice)
+	_, err := bc.apiCall(NewPublicRequest(tickerPriceInfoEndpoint).Set("symbol", symbol), &tickerPrice)
 	return tickerPrice, err
 }
+
+// TickerPrices fetches the prices of every symbol in a single call
+func (bc *BinanceClient) TickerPrices() (BinanceTickerPrices, error) {
+	tickerPrices := BinanceTickerPrices{}
+	_, err := bc.apiCall(NewPublicRequest(tickerPriceInfoEndpoint), &tickerPrices)
+	return tickerPrices, err
+}
+
+// TickerPricesFor fetches the prices of the given symbols in a single call
+func (bc *BinanceClient) TickerPricesFor(symbols []string) (BinanceTickerPrices, error) {
+	tickerPrices := BinanceTickerPrices{}
+	encodedSymbols, err := json.Marshal(symbols)
+	if err != nil {
+		return tickerPrices, err
+	}
+
+	_, err = bc.apiCall(NewPublicRequest(tickerPriceInfoEndpoint).Set("symbols", string(encodedSymbols)), &tickerPrices)
+	return tickerPrices, err
+}
+
+// AvgPrice fetches the average price of a symbol over the last minutes
+func (bc *BinanceClient) AvgPrice(symbol string) (*BinanceAvgPrice, error) {
+	avgPrice := &BinanceAvgPrice{}
+	_, err := bc.apiCall(NewPublicRequest(avgPriceEndpoint).Set("symbol", symbol), &avgPrice)
+	return avgPrice, err
+}
+
+// Tickers24hr fetches the 24 hours statistics of every symbol in a single call
+func (bc *BinanceClient) Tickers24hr() (BinanceTickers24hr, error) {
+	tickers := BinanceTickers24hr{}
+	_, err := bc.apiCall(NewPublicRequest(ticker24hrEndpoint), &tickers)
+	return tickers, err
+}
+
+// BookTickers fetches the best bid and ask of every symbol in a single call
+func (bc *BinanceClient) BookTickers() (BinanceBookTickers, error) {
+	tickers := BinanceBookTickers{}
+	_, err := bc.apiCall(NewPublicRequest(bookTickerEndpoint), &tickers)
+	return tickers, err
+}
+
+// TickerPrice returns the last price of a symbol, from the prices of every symbol fetched on the first call
+func (bc *BinanceClient) TickerPrice(symbol string) (decimal.Decimal, error) {
+	return bc.Price(PriceSourceLast, symbol)
+}
+
+// Price returns the price of a symbol from the given source.
+// Sources served for every symbol at once are fetched on the first call and cached for the run.
+func (bc *BinanceClient) Price(source, symbol string) (decimal.Decimal, error) {
+	if source == PriceSourceAvg {
+		avgPrice, err := bc.AvgPrice(symbol)
+		if err != nil {
+			return decimal.Zero, err
+		}
+		return avgPrice.Price, nil
+	}
+
+	if bc.prices == nil {
+		bc.prices = make(map[string]map[string]decimal.Decimal)
+	}
+
+	prices, ok := bc.prices[source]
+	if !ok {
+		var err error
+		prices, err = bc.fetchPrices(source)
+		if err != nil {
+			return decimal.Zero, err
+		}
+		bc.prices[source] = prices
+	}
+
+	price, ok := prices[symbol]
+	if !ok {
+		return decimal.Zero, fmt.Errorf("no %s price for %s", source, symbol)
+	}
+
+	return price, nil
+}
+
+// fetchPrices fetches the prices of every symbol from a source, indexed by symbol
+func (bc *BinanceClient) fetchPrices(source string) (map[string]decimal.Decimal, error) {
+	prices := make(map[string]decimal.Decimal)
+
+	switch source {
+	case PriceSourceLast:
+		tickerPrices, err := bc.TickerPrices()
+		if err != nil {
+			return nil, err
+		}
+		return tickerPrices.Registry(), nil
+	case PriceSourceVwap:
+		tickers, err := bc.Tickers24hr()
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range tickers {
+			prices[t.Symbol] = t.WeightedAvgPrice
+		}
+	case PriceSourceMid:
+		tickers, err := bc.BookTickers()
+		if err != nil {
+			return nil, err
+		}
+		// One-sided books are left out, Price reports them as having no mid price
+		for _, t := range tickers {
+			if mid, err := t.Mid(); err == nil {
+				prices[t.Symbol] = mid
+			}
+		}
+	default:
+		return nil, ValidatePriceSource(source)
+	}
+
+	return prices, nil
+}
@@ -0,0 +1,90 @@
+package binance
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/openware/binance-cli/pkg/helpers"
+)
+
+// Well-known Binance error codes, see https://binance-docs.github.io/apidocs/spot/en/#error-codes
+const (
+	ErrCodeTooManyRequests     = -1003
+	ErrCodeTimestampOutOfRange = -1021
+	ErrCodeInvalidSignature    = -1022
+	ErrCodeInvalidApiKeyFormat = -2014
+	ErrCodeRejectedApiKey      = -2015
+)
+
+// APIError is the code and message Binance explains a failure with, see Hint for the codes with a known fix
+type APIError struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("Binance error %d: %s", e.Code, e.Msg)
+}
+
+// HTTPError is a Binance response with a non-2xx status, its APIError is a *APIError when Binance explained the failure
+type HTTPError = helpers.HTTPError
+
+func newHTTPError(statusCode int, endpoint string, body []byte) *HTTPError {
+	httpErr := &HTTPError{StatusCode: statusCode, Endpoint: endpoint, Body: string(body)}
+
+	apiErr := &APIError{}
+	if err := json.Unmarshal(body, apiErr); err == nil && (apiErr.Code != 0 || apiErr.Msg != "") {
+		httpErr.APIError = apiErr
+	}
+
+	return httpErr
+}
+
+// Hint returns what the user can do about a Binance error, empty when there is no known fix
+func Hint(err error) string {
+	var banErr *BanError
+	if errors.As(err, &banErr) {
+		return "this IP is banned by Binance for sending too many requests, wait for the ban to be lifted"
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.Code {
+		case ErrCodeRejectedApiKey:
+			return "check BINANCE_API_KEY, that the key is allowed to read wallet data and that this IP is whitelisted for it"
+		case ErrCodeInvalidApiKeyFormat:
+			return "check BINANCE_API_KEY, it is not a Binance API key"
+		case ErrCodeInvalidSignature:
+			return "check BINANCE_SECRET, it does not match BINANCE_API_KEY"
+		case ErrCodeTimestampOutOfRange:
+			return "sync the local clock with NTP, or raise BINANCE_RECV_WINDOW on a slow network"
+		case ErrCodeTooManyRequests:
+			return "this IP sends too many requests to Binance, wait before running the command again"
+		}
+	}
+
+	return ""
+}
+
+// BanError is a request refused because Binance banned this IP, every request fails until the ban is lifted
+type BanError struct {
+	Until time.Time
+	// Err is the 418 response which started the ban, nil when the request was not sent
+	Err error
+}
+
+func (e *BanError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s until %s, stopping: %s", IpBannedError, e.Until.Format(time.RFC3339), e.Err)
+	}
+	return fmt.Sprintf("%s until %s", IpBannedError, e.Until.Format(time.RFC3339))
+}
+
+func (e *BanError) Unwrap() error {
+	return e.Err
+}
+
+// TransportError is a request to Binance which got no response
+type TransportError = helpers.TransportError
@@ -0,0 +1,114 @@
+package binance
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openware/binance-cli/pkg/helpers"
+	"gotest.tools/assert"
+)
+
+func TestHTTPError(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(tickerPriceInfoEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"code":-1121,"msg":"Invalid symbol."}`)
+	})
+
+	_, err := binanceClient.TickerPriceInfo("XYZ")
+
+	var httpErr *HTTPError
+	assert.Assert(t, errors.As(err, &httpErr))
+	assert.Equal(t, http.StatusBadRequest, httpErr.StatusCode)
+	assert.Equal(t, tickerPriceInfoEndpoint+"?symbol=XYZ", httpErr.Endpoint)
+	assert.Equal(t, `{"code":-1121,"msg":"Invalid symbol."}`, httpErr.Body)
+}
+
+func TestServerErrorRetries(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	var slept []time.Duration
+	binanceClient.limiter.sleep = func(d time.Duration) { slept = append(slept, d) }
+	binanceClient.SetRetryPolicy(helpers.RetryPolicy{MaxRetries: 2, BaseDelay: time.Second, MaxDelay: time.Minute})
+
+	calls := 0
+	mux.HandleFunc(tickerPriceInfoEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, fixture("ticker_price_ethusdt.json"))
+	})
+
+	res, err := binanceClient.TickerPriceInfo(ticker)
+	assert.NilError(t, err)
+	assert.Equal(t, "3500", res.Price.String())
+	assert.Equal(t, 2, len(slept))
+
+	t.Run("gives up after the max retries", func(t *testing.T) {
+		calls = -10
+		_, err := binanceClient.TickerPriceInfo(ticker)
+
+		var httpErr *HTTPError
+		assert.Assert(t, errors.As(err, &httpErr))
+		assert.Equal(t, http.StatusServiceUnavailable, httpErr.StatusCode)
+		assert.Equal(t, -7, calls)
+	})
+}
+
+func TestTransportError(t *testing.T) {
+	client := NewBinanceClient("", "", "http://127.0.0.1:1")
+	client.SetRetryPolicy(helpers.RetryPolicy{})
+
+	_, err := client.TickerPriceInfo(ticker)
+
+	var transportErr *TransportError
+	assert.Assert(t, errors.As(err, &transportErr))
+	assert.Equal(t, tickerPriceInfoEndpoint+"?symbol="+ticker, transportErr.Endpoint)
+}
+
+func TestAPIError(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(serverTimeEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"serverTime":%d}`, FormatTimestamp(time.Now()))
+	})
+
+	mux.HandleFunc(coinsInfoEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"code":-2015,"msg":"Invalid API-key, IP, or permissions for action."}`)
+	})
+
+	_, err := binanceClient.CoinsInfo()
+
+	var apiErr *APIError
+	assert.Assert(t, errors.As(err, &apiErr))
+	assert.Equal(t, ErrCodeRejectedApiKey, apiErr.Code)
+	assert.Equal(t, "Invalid API-key, IP, or permissions for action.", apiErr.Msg)
+	assert.ErrorContains(t, err, "401 Unauthorized: Binance error -2015: Invalid API-key, IP, or permissions for action.")
+	assert.Assert(t, strings.Contains(Hint(err), "BINANCE_API_KEY"))
+}
+
+func TestHint(t *testing.T) {
+	timestampErr := newHTTPError(http.StatusBadRequest, serverTimeEndpoint, []byte(`{"code":-1021,"msg":"Timestamp for this request is outside of the recvWindow."}`))
+	assert.Assert(t, strings.Contains(Hint(fmt.Errorf("wrapped: %w", timestampErr)), "BINANCE_RECV_WINDOW"))
+
+	bannedErr := &BanError{Until: time.Now().Add(time.Hour), Err: newHTTPError(http.StatusTeapot, serverTimeEndpoint, []byte(`{"code":-1003,"msg":"Way too many requests; IP banned until 1659146400000."}`))}
+	assert.Assert(t, strings.Contains(Hint(fmt.Errorf("wrapped: %w", bannedErr)), "banned"))
+	assert.Assert(t, strings.Contains(Hint(&BanError{Until: time.Now().Add(time.Hour)}), "banned"))
+
+	plainErr := newHTTPError(http.StatusBadGateway, serverTimeEndpoint, []byte(`<html>Bad Gateway</html>`))
+	assert.Assert(t, plainErr.APIError == nil)
+	assert.Equal(t, "", Hint(plainErr))
+	assert.Equal(t, "", Hint(nil))
+}
@@ -0,0 +1,322 @@
+package binance
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// Symbol filter types, see https://binance-docs.github.io/apidocs/spot/en/#filters
+const (
+	PriceFilterType         = "PRICE_FILTER"
+	PercentPriceFilterType  = "PERCENT_PRICE"
+	PercentPriceBySideType  = "PERCENT_PRICE_BY_SIDE"
+	LotSizeFilterType       = "LOT_SIZE"
+	MarketLotSizeFilterType = "MARKET_LOT_SIZE"
+	MinNotionalFilterType   = "MIN_NOTIONAL"
+	NotionalFilterType      = "NOTIONAL"
+	IcebergPartsFilterType  = "ICEBERG_PARTS"
+	MaxNumOrdersFilterType  = "MAX_NUM_ORDERS"
+	MaxNumAlgoOrdersType    = "MAX_NUM_ALGO_ORDERS"
+	MaxNumIcebergOrdersType = "MAX_NUM_ICEBERG_ORDERS"
+	MaxPositionFilterType   = "MAX_POSITION"
+)
+
+// Filter is a symbol filter as sent by Binance, holding the fields of every filter type.
+// Use the typed accessors of BinanceMarket to read a given filter type.
+type Filter struct {
+	Type string `json:"filterType"`
+
+	// PRICE_FILTER
+	MinPrice decimal.Decimal `json:"minPrice,omitempty"`
+	MaxPrice decimal.Decimal `json:"maxPrice,omitempty"`
+	TickSize decimal.Decimal `json:"tickSize,omitempty"`
+
+	// LOT_SIZE, MARKET_LOT_SIZE
+	MinQuantity decimal.Decimal `json:"minQty,omitempty"`
+	MaxQuantity decimal.Decimal `json:"maxQty,omitempty"`
+	StepSize    decimal.Decimal `json:"stepSize,omitempty"`
+
+	// MIN_NOTIONAL, NOTIONAL
+	MinNotional      decimal.Decimal `json:"minNotional,omitempty"`
+	MaxNotional      decimal.Decimal `json:"maxNotional,omitempty"`
+	ApplyToMarket    bool            `json:"applyToMarket,omitempty"`
+	ApplyMinToMarket bool            `json:"applyMinToMarket,omitempty"`
+	ApplyMaxToMarket bool            `json:"applyMaxToMarket,omitempty"`
+
+	// PERCENT_PRICE, PERCENT_PRICE_BY_SIDE, MIN_NOTIONAL, NOTIONAL
+	AvgPriceMins int `json:"avgPriceMins,omitempty"`
+
+	// PERCENT_PRICE
+	MultiplierUp   decimal.Decimal `json:"multiplierUp,omitempty"`
+	MultiplierDown decimal.Decimal `json:"multiplierDown,omitempty"`
+
+	// PERCENT_PRICE_BY_SIDE
+	BidMultiplierUp   decimal.Decimal `json:"bidMultiplierUp,omitempty"`
+	BidMultiplierDown decimal.Decimal `json:"bidMultiplierDown,omitempty"`
+	AskMultiplierUp   decimal.Decimal `json:"askMultiplierUp,omitempty"`
+	AskMultiplierDown decimal.Decimal `json:"askMultiplierDown,omitempty"`
+
+	// ICEBERG_PARTS
+	Limit int `json:"limit,omitempty"`
+
+	// MAX_NUM_ORDERS, MAX_NUM_ALGO_ORDERS, MAX_NUM_ICEBERG_ORDERS
+	MaxNumOrders        int `json:"maxNumOrders,omitempty"`
+	MaxNumAlgoOrders    int `json:"maxNumAlgoOrders,omitempty"`
+	MaxNumIcebergOrders int `json:"maxNumIcebergOrders,omitempty"`
+
+	// MAX_POSITION
+	MaxPosition decimal.Decimal `json:"maxPosition,omitempty"`
+}
+
+// PriceFilter defines the price rules of a symbol
+type PriceFilter struct {
+	MinPrice decimal.Decimal
+	MaxPrice decimal.Decimal
+	TickSize decimal.Decimal
+}
+
+// LotSizeFilter defines the quantity rules of a symbol, for LOT_SIZE and MARKET_LOT_SIZE
+type LotSizeFilter struct {
+	MinQuantity decimal.Decimal
+	MaxQuantity decimal.Decimal
+	StepSize    decimal.Decimal
+}
+
+// NotionalFilter defines the notional value rules of a symbol, for MIN_NOTIONAL and NOTIONAL.
+// MaxNotional is zero for MIN_NOTIONAL.
+type NotionalFilter struct {
+	MinNotional      decimal.Decimal
+	MaxNotional      decimal.Decimal
+	ApplyMinToMarket bool
+	ApplyMaxToMarket bool
+	AvgPriceMins     int
+}
+
+// PercentPriceFilter defines the price range relative to the average price, for PERCENT_PRICE and PERCENT_PRICE_BY_SIDE.
+// PERCENT_PRICE applies the same multipliers to both sides.
+type PercentPriceFilter struct {
+	BidMultiplierUp   decimal.Decimal
+	BidMultiplierDown decimal.Decimal
+	AskMultiplierUp   decimal.Decimal
+	AskMultiplierDown decimal.Decimal
+	AvgPriceMins      int
+}
+
+// MaxNumOrdersFilter defines the maximum number of open orders, for MAX_NUM_ORDERS, MAX_NUM_ALGO_ORDERS and MAX_NUM_ICEBERG_ORDERS
+type MaxNumOrdersFilter struct {
+	MaxNumOrders int
+}
+
+// MaxPositionFilter defines the maximum position of an account in the base unit
+type MaxPositionFilter struct {
+	MaxPosition decimal.Decimal
+}
+
+// IcebergPartsFilter defines the maximum number of parts of an iceberg order
+type IcebergPartsFilter struct {
+	Limit int
+}
+
+func (m *BinanceMarket) GetFilter(filterType string) (*Filter, error) {
+	for _, f := range m.Filters {
+		if f.Type == filterType {
+			return &f, nil
+		}
+	}
+
+	return nil, fmt.Errorf("Filter %s not found", filterType)
+}
+
+func (m *BinanceMarket) PriceFilter() (*PriceFilter, error) {
+	f, err := m.GetFilter(PriceFilterType)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PriceFilter{
+		MinPrice: f.MinPrice,
+		MaxPrice: f.MaxPrice,
+		TickSize: f.TickSize,
+	}, nil
+}
+
+func (m *BinanceMarket) LotSizeFilter() (*LotSizeFilter, error) {
+	return m.lotSizeFilter(LotSizeFilterType)
+}
+
+func (m *BinanceMarket) MarketLotSizeFilter() (*LotSizeFilter, error) {
+	return m.lotSizeFilter(MarketLotSizeFilterType)
+}
+
+func (m *BinanceMarket) lotSizeFilter(filterType string) (*LotSizeFilter, error) {
+	f, err := m.GetFilter(filterType)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LotSizeFilter{
+		MinQuantity: f.MinQuantity,
+		MaxQuantity: f.MaxQuantity,
+		StepSize:    f.StepSize,
+	}, nil
+}
+
+// Validate checks that the min quantity can be reached in steps, a zero step size disables the check
+func (f *LotSizeFilter) Validate() error {
+	if f.StepSize.IsZero() {
+		return nil
+	}
+
+	if !f.MinQuantity.Mod(f.StepSize).IsZero() {
+		return fmt.Errorf("min quantity %s is not a multiple of step size %s", f.MinQuantity, f.StepSize)
+	}
+
+	return nil
+}
+
+// Inconsistencies lists the LOT_SIZE and MARKET_LOT_SIZE rules which contradict each other
+func (m *BinanceMarket) Inconsistencies() []string {
+	var inconsistencies []string
+
+	for _, filterType := range []string{LotSizeFilterType, MarketLotSizeFilterType} {
+		f, err := m.lotSizeFilter(filterType)
+		if err != nil {
+			continue
+		}
+
+		if err := f.Validate(); err != nil {
+			inconsistencies = append(inconsistencies, fmt.Sprintf("%s of %s: %s", filterType, m.Symbol, err))
+		}
+	}
+
+	return inconsistencies
+}
+
+// NotionalFilter returns the NOTIONAL filter, or the legacy MIN_NOTIONAL one for symbols not migrated yet
+func (m *BinanceMarket) NotionalFilter() (*NotionalFilter, error) {
+	if f, err := m.GetFilter(NotionalFilterType); err == nil {
+		return &NotionalFilter{
+			MinNotional:      f.MinNotional,
+			MaxNotional:      f.MaxNotional,
+			ApplyMinToMarket: f.ApplyMinToMarket,
+			ApplyMaxToMarket: f.ApplyMaxToMarket,
+			AvgPriceMins:     f.AvgPriceMins,
+		}, nil
+	}
+
+	f, err := m.GetFilter(MinNotionalFilterType)
+	if err != nil {
+		return nil, fmt.Errorf("Filter %s or %s not found", NotionalFilterType, MinNotionalFilterType)
+	}
+
+	return &NotionalFilter{
+		MinNotional:      f.MinNotional,
+		ApplyMinToMarket: f.ApplyToMarket,
+		AvgPriceMins:     f.AvgPriceMins,
+	}, nil
+}
+
+// PercentPriceFilter returns the PERCENT_PRICE_BY_SIDE filter, or the PERCENT_PRICE one
+func (m *BinanceMarket) PercentPriceFilter() (*PercentPriceFilter, error) {
+	if f, err := m.GetFilter(PercentPriceBySideType); err == nil {
+		return &PercentPriceFilter{
+			BidMultiplierUp:   f.BidMultiplierUp,
+			BidMultiplierDown: f.BidMultiplierDown,
+			AskMultiplierUp:   f.AskMultiplierUp,
+			AskMultiplierDown: f.AskMultiplierDown,
+			AvgPriceMins:      f.AvgPriceMins,
+		}, nil
+	}
+
+	f, err := m.GetFilter(PercentPriceFilterType)
+	if err != nil {
+		return nil, fmt.Errorf("Filter %s or %s not found", PercentPriceBySideType, PercentPriceFilterType)
+	}
+
+	return &PercentPriceFilter{
+		BidMultiplierUp:   f.MultiplierUp,
+		BidMultiplierDown: f.MultiplierDown,
+		AskMultiplierUp:   f.MultiplierUp,
+		AskMultiplierDown: f.MultiplierDown,
+		AvgPriceMins:      f.AvgPriceMins,
+	}, nil
+}
+
+func (m *BinanceMarket) MaxNumOrdersFilter() (*MaxNumOrdersFilter, error) {
+	f, err := m.GetFilter(MaxNumOrdersFilterType)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MaxNumOrdersFilter{MaxNumOrders: f.MaxNumOrders}, nil
+}
+
+func (m *BinanceMarket) MaxNumAlgoOrdersFilter() (*MaxNumOrdersFilter, error) {
+	f, err := m.GetFilter(MaxNumAlgoOrdersType)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MaxNumOrdersFilter{MaxNumOrders: f.MaxNumAlgoOrders}, nil
+}
+
+func (m *BinanceMarket) MaxNumIcebergOrdersFilter() (*MaxNumOrdersFilter, error) {
+	f, err := m.GetFilter(MaxNumIcebergOrdersType)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MaxNumOrdersFilter{MaxNumOrders: f.MaxNumIcebergOrders}, nil
+}
+
+func (m *BinanceMarket) MaxPositionFilter() (*MaxPositionFilter, error) {
+	f, err := m.GetFilter(MaxPositionFilterType)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MaxPositionFilter{MaxPosition: f.MaxPosition}, nil
+}
+
+func (m *BinanceMarket) IcebergPartsFilter() (*IcebergPartsFilter, error) {
+	f, err := m.GetFilter(IcebergPartsFilterType)
+	if err != nil {
+		return nil, err
+	}
+
+	return &IcebergPartsFilter{Limit: f.Limit}, nil
+}
+
+// invert converts the filter of a market to the filter of the inverted market, given the price of the base unit
+func (f Filter) invert(price decimal.Decimal) Filter {
+	switch f.Type {
+	case PriceFilterType:
+		f.MinPrice, f.MaxPrice = inverse(f.MaxPrice), inverse(f.MinPrice)
+		// A tick of the original price moves the inverted price by about tick / price^2
+		f.TickSize = f.TickSize.Div(price.Mul(price))
+	case LotSizeFilterType, MarketLotSizeFilterType:
+		f.MinQuantity = f.MinQuantity.Mul(price)
+		f.MaxQuantity = f.MaxQuantity.Mul(price)
+		f.StepSize = f.StepSize.Mul(price)
+	case MinNotionalFilterType, NotionalFilterType:
+		f.MinNotional = f.MinNotional.Div(price)
+		f.MaxNotional = f.MaxNotional.Div(price)
+	case PercentPriceFilterType:
+		f.MultiplierUp, f.MultiplierDown = inverse(f.MultiplierDown), inverse(f.MultiplierUp)
+	case PercentPriceBySideType:
+		// Bids of the inverted market are asks of the original one
+		f.BidMultiplierUp, f.BidMultiplierDown, f.AskMultiplierUp, f.AskMultiplierDown =
+			inverse(f.AskMultiplierDown), inverse(f.AskMultiplierUp), inverse(f.BidMultiplierDown), inverse(f.BidMultiplierUp)
+	}
+
+	return f
+}
+
+// inverse returns 1 / d, or zero for a zero (disabled) limit
+func inverse(d decimal.Decimal) decimal.Decimal {
+	if d.IsZero() {
+		return decimal.Zero
+	}
+	return decimal.NewFromInt(1).Div(d)
+}
@@ -0,0 +1,142 @@
+package binance
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"gotest.tools/assert"
+)
+
+func TestBinanceMarketFilters(t *testing.T) {
+	var bm BinanceMarket
+	err := json.Unmarshal([]byte(`{
+		"symbol": "BTCUSDT",
+		"baseAsset": "BTC",
+		"quoteAsset": "USDT",
+		"quotePrecision": 8,
+		"filters": [
+			{"filterType": "PRICE_FILTER", "minPrice": "0.01000000", "maxPrice": "1000000.00000000", "tickSize": "0.01000000"},
+			{"filterType": "LOT_SIZE", "minQty": "0.00001000", "maxQty": "9000.00000000", "stepSize": "0.00001000"},
+			{"filterType": "MARKET_LOT_SIZE", "minQty": "0.00100000", "maxQty": "100.00000000", "stepSize": "0.00000000"},
+			{"filterType": "ICEBERG_PARTS", "limit": 10},
+			{"filterType": "TRAILING_DELTA", "minTrailingAboveDelta": 10},
+			{"filterType": "PERCENT_PRICE_BY_SIDE", "bidMultiplierUp": "5", "bidMultiplierDown": "0.2", "askMultiplierUp": "5", "askMultiplierDown": "0.2", "avgPriceMins": 5},
+			{"filterType": "NOTIONAL", "minNotional": "5.00000000", "applyMinToMarket": true, "maxNotional": "9000000.00000000", "applyMaxToMarket": false, "avgPriceMins": 5},
+			{"filterType": "MAX_NUM_ORDERS", "maxNumOrders": 200},
+			{"filterType": "MAX_NUM_ALGO_ORDERS", "maxNumAlgoOrders": 5}
+		]
+	}`), &bm)
+	require.NoError(t, err)
+
+	lotSize, err := bm.LotSizeFilter()
+	require.NoError(t, err)
+	assert.Equal(t, "0.00001", lotSize.StepSize.String())
+	assert.Equal(t, "9000", lotSize.MaxQuantity.String())
+
+	marketLotSize, err := bm.MarketLotSizeFilter()
+	require.NoError(t, err)
+	assert.Equal(t, "0.001", marketLotSize.MinQuantity.String())
+
+	notional, err := bm.NotionalFilter()
+	require.NoError(t, err)
+	assert.Equal(t, "5", notional.MinNotional.String())
+	assert.Equal(t, "9000000", notional.MaxNotional.String())
+	assert.Equal(t, true, notional.ApplyMinToMarket)
+
+	percentPrice, err := bm.PercentPriceFilter()
+	require.NoError(t, err)
+	assert.Equal(t, "0.2", percentPrice.AskMultiplierDown.String())
+
+	iceberg, err := bm.IcebergPartsFilter()
+	require.NoError(t, err)
+	assert.Equal(t, 10, iceberg.Limit)
+
+	maxNumOrders, err := bm.MaxNumOrdersFilter()
+	require.NoError(t, err)
+	assert.Equal(t, 200, maxNumOrders.MaxNumOrders)
+
+	maxNumAlgoOrders, err := bm.MaxNumAlgoOrdersFilter()
+	require.NoError(t, err)
+	assert.Equal(t, 5, maxNumAlgoOrders.MaxNumOrders)
+
+	_, err = bm.MaxPositionFilter()
+	assert.Error(t, err, "Filter MAX_POSITION not found")
+
+	t.Run("min amount falls back to NOTIONAL", func(t *testing.T) {
+		assert.Equal(t, "0.000105", bm.CalculateMinAmount(decimal.NewFromInt(50000), DefaultMinNotionalMultiplier, decimal.Zero).String())
+	})
+
+	t.Run("min amount covers MARKET_LOT_SIZE", func(t *testing.T) {
+		odxm, err := bm.ToOpendaxMarket(bm.CalculateMinAmount(decimal.NewFromInt(50000), DefaultMinNotionalMultiplier, decimal.Zero))
+		require.NoError(t, err)
+		assert.Equal(t, "0.001", odxm.MinAmount.String())
+	})
+}
+
+func TestPercentPriceFilterFallback(t *testing.T) {
+	bm := BinanceMarket{
+		Filters: []Filter{
+			{
+				Type:           PercentPriceFilterType,
+				MultiplierUp:   decimal.RequireFromString("5"),
+				MultiplierDown: decimal.RequireFromString("0.2"),
+			},
+		},
+	}
+
+	percentPrice, err := bm.PercentPriceFilter()
+	require.NoError(t, err)
+	assert.Equal(t, "5", percentPrice.BidMultiplierUp.String())
+	assert.Equal(t, "0.2", percentPrice.AskMultiplierDown.String())
+
+	_, err = (&BinanceMarket{}).NotionalFilter()
+	assert.Error(t, err, "Filter NOTIONAL or MIN_NOTIONAL not found")
+}
+
+func TestLotSizeFilterValidate(t *testing.T) {
+	bm := &BinanceMarket{
+		Symbol: "XYZUSDT",
+		Filters: []Filter{
+			{
+				Type:        LotSizeFilterType,
+				MinQuantity: decimal.RequireFromString("0.15"),
+				StepSize:    decimal.RequireFromString("0.1"),
+			},
+			{
+				Type:        MarketLotSizeFilterType,
+				MinQuantity: decimal.RequireFromString("0.2"),
+			},
+		},
+	}
+
+	assert.DeepEqual(t, []string{"LOT_SIZE of XYZUSDT: min quantity 0.15 is not a multiple of step size 0.1"}, bm.Inconsistencies())
+	assert.Equal(t, 0, len(expectedMarket.Inconsistencies()))
+}
+
+func TestAmountPrecisionFromStepSize(t *testing.T) {
+	bm := &BinanceMarket{
+		Symbol:         "XYZUSDT",
+		BaseUnit:       "XYZ",
+		QuoteUnit:      "USDT",
+		QuotePrecision: decimal.RequireFromString("8"),
+		Filters: []Filter{
+			{
+				Type:     PriceFilterType,
+				MinPrice: decimal.RequireFromString("0.0001"),
+				TickSize: decimal.RequireFromString("0.0001"),
+			},
+			{
+				Type:        LotSizeFilterType,
+				MinQuantity: decimal.RequireFromString("1"),
+				StepSize:    decimal.RequireFromString("0.1"),
+			},
+		},
+	}
+
+	odxm, err := bm.ToOpendaxMarket(decimal.RequireFromString("1.26"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), odxm.AmountPrecision)
+	assert.Equal(t, "1.3", odxm.MinAmount.String())
+}
@@ -0,0 +1,30 @@
+package binance
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Price sources the min amount can be derived from
+const (
+	// PriceSourceLast is the last traded price
+	PriceSourceLast = "last"
+	// PriceSourceAvg is the average price over the last minutes, as used by the PERCENT_PRICE filters
+	PriceSourceAvg = "avg"
+	// PriceSourceVwap is the volume weighted average price over the last 24 hours
+	PriceSourceVwap = "vwap"
+	// PriceSourceMid is the price between the best bid and ask
+	PriceSourceMid = "mid"
+)
+
+var PriceSources = []string{PriceSourceLast, PriceSourceAvg, PriceSourceVwap, PriceSourceMid}
+
+// ValidatePriceSource checks that the price source is supported
+func ValidatePriceSource(source string) error {
+	for _, s := range PriceSources {
+		if s == source {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown price source %q, expected one of %s", source, strings.Join(PriceSources, ", "))
+}
@@ -0,0 +1,136 @@
+package binance
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	RequestWeightLimitType = "REQUEST_WEIGHT"
+	usedWeightHeaderPrefix = "X-MBX-USED-WEIGHT-"
+	// maxRateLimitRetries is the number of times a request rejected with 429 is sent again
+	maxRateLimitRetries = 3
+)
+
+// WeightThrottleRatio is the share of a weight limit after which requests wait for the next interval
+var WeightThrottleRatio = 0.9
+
+// RateLimit is a limit declared by Binance in exchangeInfo
+type RateLimit struct {
+	RateLimitType string `json:"rateLimitType"`
+	Interval      string `json:"interval"`
+	IntervalNum   int    `json:"intervalNum"`
+	Limit         int    `json:"limit"`
+}
+
+// Key returns the interval as written in the used weight headers, 1M for one minute
+func (l RateLimit) Key() string {
+	if l.Interval == "" {
+		return ""
+	}
+	return fmt.Sprintf("%d%s", l.IntervalNum, l.Interval[:1])
+}
+
+// Duration returns the length of the interval
+func (l RateLimit) Duration() time.Duration {
+	unit := map[string]time.Duration{
+		"SECOND": time.Second,
+		"MINUTE": time.Minute,
+		"HOUR":   time.Hour,
+		"DAY":    24 * time.Hour,
+	}[l.Interval]
+	return time.Duration(l.IntervalNum) * unit
+}
+
+// rateLimiter tracks the request weight used by the client against the limits declared by Binance
+type rateLimiter struct {
+	limits      []RateLimit
+	usedWeight  map[string]int
+	usedAt      time.Time
+	bannedUntil time.Time
+	// sleep is replaced in tests
+	sleep func(time.Duration)
+}
+
+func (rl *rateLimiter) setLimits(limits []RateLimit) {
+	rl.limits = nil
+	for _, l := range limits {
+		if l.RateLimitType == RequestWeightLimitType && l.Duration() > 0 {
+			rl.limits = append(rl.limits, l)
+		}
+	}
+}
+
+// update records the used weight sent back by Binance
+func (rl *rateLimiter) update(header http.Header) {
+	for name, values := range header {
+		name = strings.ToUpper(name)
+		if !strings.HasPrefix(name, usedWeightHeaderPrefix) || len(values) == 0 {
+			continue
+		}
+
+		weight, err := strconv.Atoi(values[0])
+		if err != nil {
+			continue
+		}
+
+		if rl.usedWeight == nil {
+			rl.usedWeight = make(map[string]int)
+		}
+		rl.usedWeight[strings.TrimPrefix(name, usedWeightHeaderPrefix)] = weight
+		rl.usedAt = time.Now()
+	}
+}
+
+// wait blocks until the next interval when the used weight is close to a limit, and fails while the IP is banned
+func (rl *rateLimiter) wait() error {
+	if time.Now().Before(rl.bannedUntil) {
+		return &BanError{Until: rl.bannedUntil}
+	}
+
+	for _, l := range rl.limits {
+		used := rl.usedWeight[l.Key()]
+		if float64(used) < WeightThrottleRatio*float64(l.Limit) {
+			continue
+		}
+
+		// Binance counts the weight in fixed windows, the weight seen in a previous window is reset
+		windowEnd := rl.usedAt.Truncate(l.Duration()).Add(l.Duration())
+		delay := time.Until(windowEnd)
+		if delay <= 0 {
+			continue
+		}
+
+		fmt.Fprintf(os.Stderr, "Binance request weight %d/%d used for %s, waiting %s\n", used, l.Limit, l.Key(), delay.Round(time.Second))
+		rl.pause(delay)
+		delete(rl.usedWeight, l.Key())
+	}
+
+	return nil
+}
+
+// ban stops every request until Binance lifts the ban
+func (rl *rateLimiter) ban(retryAfter time.Duration) {
+	rl.bannedUntil = time.Now().Add(retryAfter)
+}
+
+func (rl *rateLimiter) pause(d time.Duration) {
+	if rl.sleep != nil {
+		rl.sleep(d)
+		return
+	}
+	time.Sleep(d)
+}
+
+// retryAfter reads the Retry-After header in seconds, or returns the fallback
+func retryAfter(header http.Header, fallback time.Duration) time.Duration {
+	seconds, err := strconv.Atoi(header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return fallback
+	}
+	return time.Duration(seconds) * time.Second
+}
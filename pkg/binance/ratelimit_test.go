@@ -0,0 +1,99 @@
+package binance
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+func TestRateLimitKey(t *testing.T) {
+	assert.Equal(t, "1M", RateLimit{Interval: "MINUTE", IntervalNum: 1}.Key())
+	assert.Equal(t, "10S", RateLimit{Interval: "SECOND", IntervalNum: 10}.Key())
+	assert.Equal(t, 10*time.Second, RateLimit{Interval: "SECOND", IntervalNum: 10}.Duration())
+}
+
+func TestWeightThrottling(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	var slept []time.Duration
+	binanceClient.limiter.sleep = func(d time.Duration) { slept = append(slept, d) }
+	binanceClient.limiter.setLimits([]RateLimit{
+		{RateLimitType: RequestWeightLimitType, Interval: "MINUTE", IntervalNum: 1, Limit: 100},
+		{RateLimitType: "ORDERS", Interval: "SECOND", IntervalNum: 10, Limit: 1},
+	})
+
+	weight := 50
+	mux.HandleFunc(tickerPriceInfoEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-MBX-USED-WEIGHT-1M", fmt.Sprint(weight))
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, fixture("ticker_price_ethusdt.json"))
+	})
+
+	_, err := binanceClient.TickerPriceInfo(ticker)
+	assert.NilError(t, err)
+	_, err = binanceClient.TickerPriceInfo(ticker)
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(slept))
+
+	weight = 95
+	_, err = binanceClient.TickerPriceInfo(ticker)
+	assert.NilError(t, err)
+	_, err = binanceClient.TickerPriceInfo(ticker)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(slept))
+	assert.Assert(t, slept[0] > 0 && slept[0] <= time.Minute)
+}
+
+func TestTooManyRequests(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	var slept []time.Duration
+	binanceClient.limiter.sleep = func(d time.Duration) { slept = append(slept, d) }
+
+	calls := 0
+	mux.HandleFunc(tickerPriceInfoEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, fixture("ticker_price_ethusdt.json"))
+	})
+
+	res, err := binanceClient.TickerPriceInfo(ticker)
+	assert.NilError(t, err)
+	assert.Equal(t, "3500", res.Price.String())
+	assert.DeepEqual(t, []time.Duration{7 * time.Second}, slept)
+}
+
+func TestIpBan(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	calls := 0
+	mux.HandleFunc(tickerPriceInfoEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(http.StatusTeapot)
+	})
+
+	_, err := binanceClient.TickerPriceInfo(ticker)
+	assert.ErrorContains(t, err, IpBannedError)
+	banErr := &BanError{}
+	assert.Assert(t, errors.As(err, &banErr))
+	assert.Assert(t, time.Until(banErr.Until) > time.Minute)
+
+	// Requests stop until the ban is lifted
+	_, err = binanceClient.TickerPriceInfo(ticker)
+	assert.ErrorContains(t, err, IpBannedError)
+	assert.Assert(t, errors.As(err, &banErr))
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,76 @@
+package binance
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// SecurityType defines how a Binance endpoint is authenticated
+type SecurityType string
+
+const (
+	// SecurityNone endpoints are public
+	SecurityNone SecurityType = "NONE"
+	// SecurityMarketData endpoints need the API key
+	SecurityMarketData SecurityType = "MARKET_DATA"
+	// SecurityUserStream endpoints need the API key
+	SecurityUserStream SecurityType = "USER_STREAM"
+	// SecurityUserData endpoints need the API key and a signature
+	SecurityUserData SecurityType = "USER_DATA"
+	// SecurityTrade endpoints need the API key and a signature
+	SecurityTrade SecurityType = "TRADE"
+)
+
+// NeedsApiKey reports whether the X-MBX-APIKEY header is required
+func (s SecurityType) NeedsApiKey() bool {
+	return s != SecurityNone && s != ""
+}
+
+// NeedsSignature reports whether the request is timestamped and signed
+func (s SecurityType) NeedsSignature() bool {
+	return s == SecurityUserData || s == SecurityTrade
+}
+
+// Request is a call to a Binance endpoint, parameters are sent in the query string
+type Request struct {
+	Method   string
+	Endpoint string
+	Params   url.Values
+	Security SecurityType
+}
+
+// NewRequest returns a request without parameters
+func NewRequest(method, endpoint string, security SecurityType) *Request {
+	return &Request{
+		Method:   method,
+		Endpoint: endpoint,
+		Params:   url.Values{},
+		Security: security,
+	}
+}
+
+// NewPublicRequest returns a GET request to a public endpoint
+func NewPublicRequest(endpoint string) *Request {
+	return NewRequest(http.MethodGet, endpoint, SecurityNone)
+}
+
+// Set sets a parameter of the request
+func (r *Request) Set(key, value string) *Request {
+	r.Params.Set(key, value)
+	return r
+}
+
+// Retryable reports whether the request can be sent again after a failure.
+// Binance reports the execution status of writes failing with a timeout or a gateway error as unknown,
+// sending them again could place an order twice, so the caller decides.
+func (r *Request) Retryable() bool {
+	return r.Method == http.MethodGet && r.Security != SecurityTrade
+}
+
+// String returns the endpoint with the parameters, without the timestamp and signature of signed requests
+func (r *Request) String() string {
+	if len(r.Params) == 0 {
+		return r.Endpoint
+	}
+	return r.Endpoint + "?" + r.Params.Encode()
+}
@@ -0,0 +1,111 @@
+package binance
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openware/binance-cli/pkg/helpers"
+	"gotest.tools/assert"
+)
+
+func TestSignedRequestBuilder(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc(serverTimeEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "", r.Header.Get("X-MBX-APIKEY"))
+		fmt.Fprintf(w, `{"serverTime":%d}`, FormatTimestamp(time.Now()))
+	})
+
+	mux.HandleFunc("/api/v3/order/test", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, binanceClient.apiKey, r.Header.Get("X-MBX-APIKEY"))
+
+		query := r.URL.Query()
+		assert.Equal(t, "ETHUSDT", query.Get("symbol"))
+		assert.Equal(t, "BUY", query.Get("side"))
+		assert.Assert(t, query.Get("timestamp") != "")
+
+		// The signature covers every parameter, not only the timestamp
+		signed := strings.Split(r.URL.RawQuery, "&signature=")[0]
+		assert.Assert(t, strings.Contains(signed, "side=BUY&symbol=ETHUSDT"))
+		mac := hmac.New(sha256.New, []byte(binanceClient.secret))
+		mac.Write([]byte(signed))
+		assert.Equal(t, fmt.Sprintf("%x", mac.Sum(nil)), query.Get("signature"))
+
+		fmt.Fprint(w, `{}`)
+	})
+
+	request := NewRequest(http.MethodPost, "/api/v3/order/test", SecurityTrade).
+		Set("symbol", "ETHUSDT").
+		Set("side", "BUY")
+	assert.Equal(t, "/api/v3/order/test?side=BUY&symbol=ETHUSDT", request.String())
+
+	_, err := binanceClient.apiCall(request, &struct{}{})
+	assert.NilError(t, err)
+}
+
+func TestSecurityType(t *testing.T) {
+	assert.Equal(t, false, SecurityNone.NeedsApiKey())
+	assert.Equal(t, true, SecurityMarketData.NeedsApiKey())
+	assert.Equal(t, false, SecurityUserStream.NeedsSignature())
+	assert.Equal(t, true, SecurityUserData.NeedsSignature())
+	assert.Equal(t, true, SecurityTrade.NeedsSignature())
+}
+
+func TestWritesAreNotRetried(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	binanceClient.SetRetryPolicy(helpers.RetryPolicy{MaxRetries: 2})
+	binanceClient.limiter.sleep = func(time.Duration) {}
+
+	mux.HandleFunc(serverTimeEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"serverTime":%d}`, FormatTimestamp(time.Now()))
+	})
+
+	calls := 0
+	mux.HandleFunc("/api/v3/order", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprint(w, `{"code":-1007,"msg":"Timeout waiting for response from backend server. Send status unknown; execution status unknown."}`)
+	})
+
+	mux.HandleFunc("/api/v3/order/dropped", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		conn, _, err := w.(http.Hijacker).Hijack()
+		assert.NilError(t, err)
+		conn.Close()
+	})
+
+	_, err := binanceClient.apiCall(NewRequest(http.MethodPost, "/api/v3/order", SecurityTrade), &struct{}{})
+	var httpErr *HTTPError
+	assert.Assert(t, errors.As(err, &httpErr))
+	assert.Equal(t, http.StatusServiceUnavailable, httpErr.StatusCode)
+	assert.Equal(t, 1, calls)
+
+	calls = 0
+	_, err = binanceClient.apiCall(NewRequest(http.MethodPost, "/api/v3/order/dropped", SecurityTrade), &struct{}{})
+	var transportErr *TransportError
+	assert.Assert(t, errors.As(err, &transportErr))
+	assert.Equal(t, 1, calls)
+
+	calls = 0
+	_, err = binanceClient.apiCall(NewRequest(http.MethodGet, "/api/v3/order", SecurityTrade), &struct{}{})
+	assert.Assert(t, err != nil)
+	assert.Equal(t, 1, calls)
+}
+
+func TestRequestRetryable(t *testing.T) {
+	assert.Equal(t, true, NewPublicRequest(exchangeInfoEndpoint).Retryable())
+	assert.Equal(t, true, NewRequest(http.MethodGet, coinsInfoEndpoint, SecurityUserData).Retryable())
+	assert.Equal(t, false, NewRequest(http.MethodGet, "/api/v3/order", SecurityTrade).Retryable())
+	assert.Equal(t, false, NewRequest(http.MethodPost, "/sapi/v1/capital/withdraw/apply", SecurityUserData).Retryable())
+	assert.Equal(t, false, NewRequest(http.MethodDelete, "/api/v3/userDataStream", SecurityUserStream).Retryable())
+}
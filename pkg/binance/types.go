@@ -3,6 +3,7 @@ package binance
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/openware/binance-cli/pkg/helpers"
 	"github.com/openware/binance-cli/pkg/opendax"
@@ -13,9 +14,22 @@ type BinanceClient struct {
 	apiKey string
 	secret string
 	url    string
+
+	// prices caches the prices of every symbol by price source, fetched once per run
+	prices map[string]map[string]decimal.Decimal
+
+	limiter rateLimiter
+	retry   helpers.RetryPolicy
+
+	// timeOffset is the Binance server time minus the local time, measured once per client
+	timeOffset time.Duration
+	timeSynced bool
+	// recvWindow is the number of milliseconds a signed request is valid for after its timestamp, 0 for the Binance default
+	recvWindow int64
 }
 
 type BinanceExchangeInfo struct {
+	RateLimits     []RateLimit     `json:"rateLimits"`
 	Symbols        []BinanceMarket `json:"symbols"`
 	MarketRegistry map[string]BinanceMarket
 }
@@ -29,29 +43,17 @@ func (info *BinanceExchangeInfo) FillRegistry() {
 
 type BinanceMarket struct {
 	Symbol         string          `json:"symbol"`
+	Status         string          `json:"status"`
+	Permissions    []string        `json:"permissions"`
 	BaseUnit       string          `json:"baseAsset"`
 	QuoteUnit      string          `json:"quoteAsset"`
 	QuotePrecision decimal.Decimal `json:"quotePrecision"`
 	Filters        []Filter        `json:"filters"`
 }
 
-type Filter struct {
-	Type        string          `json:"filterType"`
-	MinPrice    decimal.Decimal `json:"minPrice,omitempty"`
-	MaxPrice    decimal.Decimal `json:"maxPrice,omitempty"`
-	TickSize    decimal.Decimal `json:"tickSize,omitempty"`
-	MinQuantity decimal.Decimal `json:"minQty,omitempty"`
-	MinNotional decimal.Decimal `json:"minNotional,omitempty"`
-}
-
-func (m *BinanceMarket) GetFilter(filterType string) (*Filter, error) {
-	for _, f := range m.Filters {
-		if f.Type == filterType {
-			return &f, nil
-		}
-	}
-
-	return nil, fmt.Errorf("Filter %s not found", filterType)
+// IsTrading reports whether the market is open for trading, as opposed to BREAK, HALT or other statuses
+func (m *BinanceMarket) IsTrading() bool {
+	return m.Status == "TRADING"
 }
 
 type BinanceCurrencies []*BinanceCurrency
@@ -67,18 +69,71 @@ type Network struct {
 	WithdrawMin decimal.Decimal `json:"withdrawMin"`
 }
 
+type BinanceServerTime struct {
+	ServerTime int64 `json:"serverTime"`
+}
+
 type BinanceTickerPrice struct {
 	Symbol string          `json:"symbol"`
 	Price  decimal.Decimal `json:"price"`
 }
 
+type BinanceTickerPrices []BinanceTickerPrice
+
+// BinanceAvgPrice is the average price of a symbol over the last minutes
+type BinanceAvgPrice struct {
+	Mins  int             `json:"mins"`
+	Price decimal.Decimal `json:"price"`
+}
+
+// BinanceTicker24hr is the price change statistics of a symbol over the last 24 hours
+type BinanceTicker24hr struct {
+	Symbol           string          `json:"symbol"`
+	WeightedAvgPrice decimal.Decimal `json:"weightedAvgPrice"`
+	LastPrice        decimal.Decimal `json:"lastPrice"`
+	HighPrice        decimal.Decimal `json:"highPrice"`
+	LowPrice         decimal.Decimal `json:"lowPrice"`
+	Volume           decimal.Decimal `json:"volume"`
+	QuoteVolume      decimal.Decimal `json:"quoteVolume"`
+}
+
+type BinanceTickers24hr []BinanceTicker24hr
+
+// BinanceBookTicker is the best bid and ask of a symbol
+type BinanceBookTicker struct {
+	Symbol   string          `json:"symbol"`
+	BidPrice decimal.Decimal `json:"bidPrice"`
+	BidQty   decimal.Decimal `json:"bidQty"`
+	AskPrice decimal.Decimal `json:"askPrice"`
+	AskQty   decimal.Decimal `json:"askQty"`
+}
+
+// Mid returns the price between the best bid and ask, a book missing a side has none
+func (t *BinanceBookTicker) Mid() (decimal.Decimal, error) {
+	if !t.BidPrice.IsPositive() || !t.AskPrice.IsPositive() {
+		return decimal.Zero, fmt.Errorf("no mid price for %s, bid %s ask %s", t.Symbol, t.BidPrice, t.AskPrice)
+	}
+	return t.BidPrice.Add(t.AskPrice).Div(decimal.NewFromInt(2)), nil
+}
+
+type BinanceBookTickers []BinanceBookTicker
+
+// Registry indexes the prices by symbol
+func (prices BinanceTickerPrices) Registry() map[string]decimal.Decimal {
+	registry := make(map[string]decimal.Decimal, len(prices))
+	for _, p := range prices {
+		registry[p.Symbol] = p.Price
+	}
+	return registry
+}
+
 func (m *BinanceMarket) ToOpendaxMarket(minAmount decimal.Decimal) (*opendax.OpendaxMarket, error) {
-	priceFilter, err := m.GetFilter("PRICE_FILTER")
+	priceFilter, err := m.PriceFilter()
 	if err != nil {
 		return nil, err
 	}
 
-	quantityFilter, err := m.GetFilter("LOT_SIZE")
+	quantityFilter, err := m.LotSizeFilter()
 	if err != nil {
 		return nil, err
 	}
@@ -86,12 +141,23 @@ func (m *BinanceMarket) ToOpendaxMarket(minAmount decimal.Decimal) (*opendax.Ope
 	tickPrecision := decimal.NewFromInt(helpers.ValuePrecision(priceFilter.TickSize))
 
 	pricePrecision := decimal.Min(tickPrecision, m.QuotePrecision)
-	amountPrecision := helpers.ValuePrecision(quantityFilter.MinQuantity)
+	// Orders are placed by steps from the min quantity, old symbols may not send the step size
+	amountStep := quantityFilter.StepSize
+	if amountStep.IsZero() {
+		amountStep = quantityFilter.MinQuantity
+	}
+	amountPrecision := helpers.ValuePrecision(amountStep)
 
 	minAmount = minAmount.Round(int32(amountPrecision))
 
-	if minAmount.LessThan(quantityFilter.MinQuantity) {
-		minAmount = quantityFilter.MinQuantity
+	// OpenDAX applies the min amount to market orders too
+	minQuantity := quantityFilter.MinQuantity
+	if marketQuantityFilter, err := m.MarketLotSizeFilter(); err == nil {
+		minQuantity = decimal.Max(minQuantity, marketQuantityFilter.MinQuantity)
+	}
+
+	if minAmount.LessThan(minQuantity) {
+		minAmount = minQuantity
 	}
 
 	return &opendax.OpendaxMarket{
@@ -102,21 +168,64 @@ func (m *BinanceMarket) ToOpendaxMarket(minAmount decimal.Decimal) (*opendax.Ope
 		MinPrice:        priceFilter.MinPrice,
 		MaxPrice:        decimal.Zero,
 		MinAmount:       minAmount,
+		MaxAmount:       quantityFilter.MaxQuantity,
 		AmountPrecision: amountPrecision,
 		PricePrecision:  pricePrecision.IntPart(),
 	}, nil
 }
 
+// Invert returns the market seen from the quote unit side, given the price of the base unit in the quote unit.
+// The symbol is kept, so ticker prices are still fetched for the original market.
+func (m BinanceMarket) Invert(price decimal.Decimal) BinanceMarket {
+	inverted := m
+	inverted.BaseUnit = m.QuoteUnit
+	inverted.QuoteUnit = m.BaseUnit
+	inverted.Filters = make([]Filter, len(m.Filters))
+
+	for i, f := range m.Filters {
+		inverted.Filters[i] = f.invert(price)
+	}
+
+	return inverted
+}
+
+// MaxPrice returns the highest price accepted by PRICE_FILTER and PERCENT_PRICE around the given price, zero when unlimited
+func (m *BinanceMarket) MaxPrice(price decimal.Decimal) decimal.Decimal {
+	var limits []decimal.Decimal
+
+	if priceFilter, err := m.PriceFilter(); err == nil {
+		limits = append(limits, priceFilter.MaxPrice)
+	}
+
+	if percentPriceFilter, err := m.PercentPriceFilter(); err == nil {
+		multiplier := decimal.Max(percentPriceFilter.BidMultiplierUp, percentPriceFilter.AskMultiplierUp)
+		limits = append(limits, price.Mul(multiplier))
+	}
+
+	maxPrice := decimal.Zero
+	for _, limit := range limits {
+		if limit.IsPositive() && (maxPrice.IsZero() || limit.LessThan(maxPrice)) {
+			maxPrice = limit
+		}
+	}
+
+	return maxPrice
+}
+
 func (m *BinanceMarket) OpendaxMarketName() string {
 	return strings.ToUpper(strings.Join([]string{m.BaseUnit, m.QuoteUnit}, "/"))
 }
 
-func (m BinanceMarket) CalculateMinAmount(price decimal.Decimal) decimal.Decimal {
-	notionalFilter, err := m.GetFilter("MIN_NOTIONAL")
-	if err != nil {
+// DefaultMinNotionalMultiplier is the cushion on top of the min notional, so the min amount covers it when the price moves
+var DefaultMinNotionalMultiplier = decimal.RequireFromString("1.05")
+
+// CalculateMinAmount returns the amount covering the min notional times the multiplier, and at least the floor in quote unit
+func (m BinanceMarket) CalculateMinAmount(price, multiplier, floor decimal.Decimal) decimal.Decimal {
+	notionalFilter, err := m.NotionalFilter()
+	if err != nil || !price.IsPositive() {
 		return decimal.Zero
 	}
 
-	// Return 105% of min amount to be sure it covers the min notional
-	return decimal.RequireFromString("1.05").Mul(notionalFilter.MinNotional).Div(price)
+	notional := decimal.Max(multiplier.Mul(notionalFilter.MinNotional), floor)
+	return notional.Div(price)
 }
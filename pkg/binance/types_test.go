@@ -163,3 +163,76 @@ func TestBinanceMarket(t *testing.T) {
 		})
 	*/
 }
+
+func TestInvertBinanceMarket(t *testing.T) {
+	price := decimal.RequireFromString("3500")
+	inverted := expectedMarket.Invert(price)
+
+	assert.Equal(t, "ETHUSDT", inverted.Symbol)
+	assert.Equal(t, "USDT", inverted.BaseUnit)
+	assert.Equal(t, "ETH", inverted.QuoteUnit)
+
+	priceFilter, err := inverted.GetFilter("PRICE_FILTER")
+	require.NoError(t, err)
+	assert.Equal(t, "0.000001", priceFilter.MinPrice.String())
+	assert.Equal(t, "100", priceFilter.MaxPrice.String())
+
+	minAmount := inverted.CalculateMinAmount(decimal.NewFromInt(1).Div(price), DefaultMinNotionalMultiplier, decimal.Zero)
+	odxm, err := inverted.ToOpendaxMarket(minAmount)
+	require.NoError(t, err)
+
+	assert.Equal(t, "usdteth", odxm.Symbol)
+	assert.Equal(t, "USDT/ETH", odxm.Name)
+	assert.Equal(t, "10.5", odxm.MinAmount.String())
+	assert.Equal(t, int64(1), odxm.AmountPrecision)
+	assert.Equal(t, int64(8), odxm.PricePrecision)
+
+	// The original market is left untouched
+	assert.Equal(t, "0.01", expectedMarket.Filters[0].TickSize.String())
+}
+
+func TestBinanceMarketIsTrading(t *testing.T) {
+	assert.Equal(t, true, expectedMarket.IsTrading())
+	assert.Equal(t, false, (&BinanceMarket{Symbol: "ETHUSDT", Status: "BREAK"}).IsTrading())
+	assert.Equal(t, false, (&BinanceMarket{Symbol: "ETHUSDT", Status: "HALT"}).IsTrading())
+}
+
+func TestBinanceMarketMaxPrice(t *testing.T) {
+	price := decimal.RequireFromString("3500")
+
+	t.Run("PRICE_FILTER", func(t *testing.T) {
+		assert.Equal(t, "1000000", expectedMarket.MaxPrice(price).String())
+	})
+
+	t.Run("PERCENT_PRICE around the ticker", func(t *testing.T) {
+		bm := expectedMarket
+		bm.Filters = append([]Filter{}, expectedMarket.Filters...)
+		bm.Filters = append(bm.Filters, Filter{
+			Type:           PercentPriceFilterType,
+			MultiplierUp:   decimal.RequireFromString("5"),
+			MultiplierDown: decimal.RequireFromString("0.2"),
+		})
+		assert.Equal(t, "17500", bm.MaxPrice(price).String())
+	})
+
+	t.Run("unlimited", func(t *testing.T) {
+		bm := BinanceMarket{Filters: []Filter{{Type: PriceFilterType}}}
+		assert.Equal(t, true, bm.MaxPrice(price).IsZero())
+	})
+}
+
+func TestBookTickerMid(t *testing.T) {
+	ticker := BinanceBookTicker{Symbol: "ETHUSDT", BidPrice: decimal.RequireFromString("3499.99"), AskPrice: decimal.RequireFromString("3500.01")}
+	mid, err := ticker.Mid()
+	assert.NilError(t, err)
+	assert.Equal(t, "3500", mid.String())
+
+	for _, oneSided := range []BinanceBookTicker{
+		{Symbol: "XYZUSDT", BidPrice: decimal.Zero, AskPrice: decimal.RequireFromString("1.2")},
+		{Symbol: "XYZUSDT", BidPrice: decimal.RequireFromString("1.1"), AskPrice: decimal.Zero},
+		{Symbol: "XYZUSDT"},
+	} {
+		_, err := oneSided.Mid()
+		assert.ErrorContains(t, err, "no mid price for XYZUSDT")
+	}
+}
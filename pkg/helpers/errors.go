@@ -0,0 +1,46 @@
+package helpers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// HttpTransportError prefixes the errors of requests which got no response
+const HttpTransportError = "HTTP Transport Error"
+
+// HTTPError is a response with a non-2xx status, shared by the Binance and OpenDAX clients
+type HTTPError struct {
+	StatusCode int
+	Endpoint   string
+	// Body is the raw response body
+	Body string
+	// APIError is the error envelope the client decoded from the body, nil when the body is not one
+	APIError error
+}
+
+func (e *HTTPError) Error() string {
+	if e.APIError != nil {
+		return fmt.Sprintf("%s: %d %s: %s", e.Endpoint, e.StatusCode, http.StatusText(e.StatusCode), e.APIError)
+	}
+	return fmt.Sprintf("%s: %d %s: %s", e.Endpoint, e.StatusCode, http.StatusText(e.StatusCode), strings.TrimSpace(e.Body))
+}
+
+// Unwrap exposes the decoded envelope, so errors.As reaches the typed error of the API
+func (e *HTTPError) Unwrap() error {
+	return e.APIError
+}
+
+// TransportError is a request which got no response, the request may or may not have reached the server
+type TransportError struct {
+	Endpoint string
+	Err      error
+}
+
+func (e *TransportError) Error() string {
+	return fmt.Sprintf("%s: %s: %s", HttpTransportError, e.Endpoint, e.Err)
+}
+
+func (e *TransportError) Unwrap() error {
+	return e.Err
+}
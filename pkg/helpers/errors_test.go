@@ -0,0 +1,27 @@
+package helpers
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPError(t *testing.T) {
+	plain := &HTTPError{StatusCode: http.StatusBadGateway, Endpoint: "/api/v2/peatio/public/markets", Body: "<html>Bad Gateway</html>\n"}
+	assert.EqualError(t, plain, "/api/v2/peatio/public/markets: 502 Bad Gateway: <html>Bad Gateway</html>")
+	assert.Nil(t, errors.Unwrap(plain))
+
+	apiErr := errors.New("market.not_found")
+	decoded := &HTTPError{StatusCode: http.StatusNotFound, Endpoint: "/api/v2/peatio/admin/markets/update", Body: `{"errors":["market.not_found"]}`, APIError: apiErr}
+	assert.EqualError(t, decoded, "/api/v2/peatio/admin/markets/update: 404 Not Found: market.not_found")
+	assert.True(t, errors.Is(decoded, apiErr))
+}
+
+func TestTransportError(t *testing.T) {
+	cause := errors.New("connection reset by peer")
+	err := &TransportError{Endpoint: "/api/v3/time", Err: cause}
+	assert.EqualError(t, err, "HTTP Transport Error: /api/v3/time: connection reset by peer")
+	assert.True(t, errors.Is(err, cause))
+}
@@ -0,0 +1,117 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy defines how requests failing with a transport error or a server error are sent again
+type RetryPolicy struct {
+	// MaxRetries is the number of times a failed request is sent again, 0 disables retries
+	MaxRetries int
+	// BaseDelay is the delay before the first retry, doubled for every next one
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between two retries
+	MaxDelay time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 3,
+	BaseDelay:  500 * time.Millisecond,
+	MaxDelay:   10 * time.Second,
+}
+
+// RetryConfig is the retry policy read from the config file, unset fields keep the default value
+type RetryConfig struct {
+	MaxRetries *int      `yaml:"max_retries" json:"max_retries"`
+	BaseDelay  *Duration `yaml:"base_delay" json:"base_delay"`
+	MaxDelay   *Duration `yaml:"max_delay" json:"max_delay"`
+}
+
+// Policy returns the default policy overridden by the configured fields
+func (c *RetryConfig) Policy() RetryPolicy {
+	policy := DefaultRetryPolicy
+	if c == nil {
+		return policy
+	}
+
+	if c.MaxRetries != nil {
+		policy.MaxRetries = *c.MaxRetries
+	}
+	if c.BaseDelay != nil {
+		policy.BaseDelay = time.Duration(*c.BaseDelay)
+	}
+	if c.MaxDelay != nil {
+		policy.MaxDelay = time.Duration(*c.MaxDelay)
+	}
+
+	return policy
+}
+
+// Duration is a time.Duration read from a "1s" like string, or from a number of nanoseconds
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	return d.set(value)
+}
+
+func (d *Duration) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var value interface{}
+	if err := unmarshal(&value); err != nil {
+		return err
+	}
+	return d.set(value)
+}
+
+func (d *Duration) set(value interface{}) error {
+	switch v := value.(type) {
+	case string:
+		duration, err := time.ParseDuration(v)
+		if err != nil {
+			return err
+		}
+		*d = Duration(duration)
+	case float64:
+		*d = Duration(v)
+	case int:
+		*d = Duration(v)
+	default:
+		return fmt.Errorf("invalid duration %v, expected a string like 1s or a number of nanoseconds", value)
+	}
+	return nil
+}
+
+// Backoff returns the delay before the given retry, starting at 1.
+// The delay is drawn between half and all of the exponential delay, so clients failing together do not retry together.
+func (p RetryPolicy) Backoff(retry int) time.Duration {
+	delay := p.BaseDelay
+	// Without a max delay the delay keeps doubling
+	for i := 1; i < retry && (p.MaxDelay <= 0 || delay < p.MaxDelay); i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(delay-half)+1))
+}
+
+// RetryableStatus reports whether a response status is worth sending the request again.
+// Writes are only retried on 503, the server refused them; after a 502 or 504 the application may still apply them.
+func RetryableStatus(method string, status int) bool {
+	if method == http.MethodGet {
+		return status >= http.StatusInternalServerError
+	}
+	return status == http.StatusServiceUnavailable
+}
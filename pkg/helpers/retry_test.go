@@ -0,0 +1,63 @@
+package helpers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 5, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	for retry, max := range map[int]time.Duration{
+		1: 100 * time.Millisecond,
+		2: 200 * time.Millisecond,
+		3: 400 * time.Millisecond,
+		5: time.Second,
+		9: time.Second,
+	} {
+		for i := 0; i < 20; i++ {
+			delay := policy.Backoff(retry)
+			assert.GreaterOrEqual(t, int64(delay), int64(max/2), retry)
+			assert.LessOrEqual(t, int64(delay), int64(max), retry)
+		}
+	}
+
+	assert.Equal(t, time.Duration(0), RetryPolicy{}.Backoff(1))
+
+	uncapped := RetryPolicy{MaxRetries: 5, BaseDelay: 100 * time.Millisecond}
+	delay := uncapped.Backoff(4)
+	assert.GreaterOrEqual(t, int64(delay), int64(400*time.Millisecond))
+	assert.LessOrEqual(t, int64(delay), int64(800*time.Millisecond))
+}
+
+func TestRetryConfig(t *testing.T) {
+	var config *RetryConfig
+	assert.Equal(t, DefaultRetryPolicy, config.Policy())
+
+	for _, body := range []string{`{"max_retries": 5}`, `{"max_retries": 5, "base_delay": "500ms"}`, `{"max_retries": 5, "max_delay": 10000000000}`} {
+		config = &RetryConfig{}
+		assert.NoError(t, json.Unmarshal([]byte(body), config), body)
+		assert.Equal(t, RetryPolicy{MaxRetries: 5, BaseDelay: 500 * time.Millisecond, MaxDelay: 10 * time.Second}, config.Policy(), body)
+	}
+
+	config = &RetryConfig{}
+	assert.NoError(t, json.Unmarshal([]byte(`{"max_retries": 0, "base_delay": "1s", "max_delay": "1m"}`), config))
+	assert.Equal(t, RetryPolicy{MaxRetries: 0, BaseDelay: time.Second, MaxDelay: time.Minute}, config.Policy())
+
+	assert.Error(t, json.Unmarshal([]byte(`{"base_delay": "soon"}`), &RetryConfig{}))
+	assert.Error(t, json.Unmarshal([]byte(`{"base_delay": true}`), &RetryConfig{}))
+}
+
+func TestRetryableStatus(t *testing.T) {
+	assert.True(t, RetryableStatus(http.MethodGet, http.StatusInternalServerError))
+	assert.True(t, RetryableStatus(http.MethodGet, http.StatusGatewayTimeout))
+	assert.True(t, RetryableStatus(http.MethodPost, http.StatusServiceUnavailable))
+	assert.False(t, RetryableStatus(http.MethodPost, http.StatusBadGateway))
+	assert.False(t, RetryableStatus(http.MethodPost, http.StatusGatewayTimeout))
+	assert.False(t, RetryableStatus(http.MethodPost, http.StatusInternalServerError))
+	assert.False(t, RetryableStatus(http.MethodGet, http.StatusNotFound))
+}
@@ -0,0 +1,32 @@
+package mapping
+
+import (
+	"strings"
+
+	"github.com/openware/binance-cli/pkg/opendax"
+)
+
+// Currency points an OpenDAX currency to the Binance coin and network it is withdrawn on
+type Currency struct {
+	Coin    string `yaml:"coin" json:"coin"`
+	Network string `yaml:"network" json:"network"`
+}
+
+// Currencies maps OpenDAX currency ids to Binance coins
+type Currencies map[string]Currency
+
+// For returns the Binance coin of an OpenDAX currency, falling back to the upper-cased currency id.
+// The network is empty when the currency should be compared with every network of the coin.
+func (m Currencies) For(currency *opendax.OpendaxCurrency) Currency {
+	mapped, ok := m[strings.ToLower(currency.Code)]
+	if !ok {
+		return Currency{Coin: currency.ToBinanceCoinName()}
+	}
+
+	if mapped.Coin == "" {
+		mapped.Coin = currency.ToBinanceCoinName()
+	}
+	mapped.Coin = strings.ToUpper(mapped.Coin)
+
+	return mapped
+}
@@ -0,0 +1,21 @@
+package mapping
+
+import (
+	"testing"
+
+	"github.com/openware/binance-cli/pkg/opendax"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCurrenciesFor(t *testing.T) {
+	currencies := Currencies{
+		"usdt-erc20": {Coin: "usdt", Network: "ETH"},
+		"usdt-trc20": {Coin: "USDT", Network: "TRX"},
+		"bnb":        {Network: "BSC"},
+	}
+
+	assert.Equal(t, Currency{Coin: "USDT", Network: "ETH"}, currencies.For(&opendax.OpendaxCurrency{Code: "usdt-erc20"}))
+	assert.Equal(t, Currency{Coin: "USDT", Network: "TRX"}, currencies.For(&opendax.OpendaxCurrency{Code: "usdt-trc20"}))
+	assert.Equal(t, Currency{Coin: "BNB", Network: "BSC"}, currencies.For(&opendax.OpendaxCurrency{Code: "bnb"}))
+	assert.Equal(t, Currency{Coin: "ETH"}, currencies.For(&opendax.OpendaxCurrency{Code: "eth"}))
+}
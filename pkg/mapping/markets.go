@@ -0,0 +1,35 @@
+package mapping
+
+import (
+	"strings"
+
+	"github.com/openware/binance-cli/pkg/opendax"
+)
+
+// Market points an OpenDAX market to the Binance market used as its reference
+type Market struct {
+	Symbol string `yaml:"symbol" json:"symbol"`
+	// Inverted is set when the Binance market quotes the OpenDAX base unit in the OpenDAX quote unit the other way round
+	Inverted bool `yaml:"inverted" json:"inverted"`
+}
+
+// Markets maps OpenDAX market symbols to Binance markets
+type Markets map[string]Market
+
+// For returns the Binance reference of an OpenDAX market, falling back to the concatenated base and quote units
+// (quote and base units for inverted markets)
+func (m Markets) For(market opendax.OpendaxMarket) Market {
+	mapped := m[strings.ToLower(market.Symbol)]
+	if mapped.Symbol != "" {
+		mapped.Symbol = strings.ToUpper(mapped.Symbol)
+		return mapped
+	}
+
+	if mapped.Inverted {
+		mapped.Symbol = strings.ToUpper(market.QuoteUnit + market.BaseUnit)
+		return mapped
+	}
+
+	mapped.Symbol = market.ToBinanceMarketName()
+	return mapped
+}
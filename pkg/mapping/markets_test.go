@@ -0,0 +1,21 @@
+package mapping
+
+import (
+	"testing"
+
+	"github.com/openware/binance-cli/pkg/opendax"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarketsFor(t *testing.T) {
+	markets := Markets{
+		"wbtcusd": {Symbol: "btcusdt"},
+		"usdtbtc": {Inverted: true},
+		"usdbtc":  {Symbol: "BTCUSDT", Inverted: true},
+	}
+
+	assert.Equal(t, Market{Symbol: "BTCUSDT"}, markets.For(opendax.OpendaxMarket{Symbol: "wbtcusd", BaseUnit: "wbtc", QuoteUnit: "usd"}))
+	assert.Equal(t, Market{Symbol: "BTCUSDT", Inverted: true}, markets.For(opendax.OpendaxMarket{Symbol: "usdtbtc", BaseUnit: "usdt", QuoteUnit: "btc"}))
+	assert.Equal(t, Market{Symbol: "BTCUSDT", Inverted: true}, markets.For(opendax.OpendaxMarket{Symbol: "usdbtc", BaseUnit: "usd", QuoteUnit: "btc"}))
+	assert.Equal(t, Market{Symbol: "ETHUSDT"}, markets.For(opendax.OpendaxMarket{Symbol: "ethusdt", BaseUnit: "eth", QuoteUnit: "usdt"}))
+}
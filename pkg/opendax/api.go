@@ -4,35 +4,33 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"net/http"
+	"os"
+	"time"
+
+	"github.com/openware/binance-cli/pkg/helpers"
 )
 
 const (
 	adminMarketsUpdateEndpoint     = "/api/v2/peatio/admin/markets/update"
+	adminMarketsCreateEndpoint     = "/api/v2/peatio/admin/markets/new"
+	adminCurrenciesUpdateEndpoint  = "/api/v2/peatio/admin/currencies/update"
 	adminFinexSecretUpdateEndpoint = "/api/v2/sonic/admin/finex/secret"
 	marketsEndpoint                = "/api/v2/peatio/public/markets"
 	currenciesEndpoint             = "/api/v2/peatio/public/currencies"
-	NotFoundError                  = "404 Record Not Found"
-	NotAuthorizedError             = "401 Not Authorized"
-	ServiceUnavailableError        = "503 Service Unavailable"
-	HttpTransportError             = "HTTP Transport Error"
+	HttpTransportError             = helpers.HttpTransportError
 )
 
 func (oc *OpendaxClient) opendaxApiCall(endpoint string, receiver interface{}) (interface{}, http.Header, int, error) {
-	uri := oc.platformUrl + endpoint
-	resp, err := http.Get(uri)
+	resp, err := oc.send(http.MethodGet, endpoint, nil)
 	if err != nil {
-		fmt.Printf("The HTTP request failed with error %s\n", err)
-		return receiver, http.Header{}, 0, fmt.Errorf(HttpTransportError)
+		return receiver, responseHeader(resp), responseStatus(resp), err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode == http.StatusNotFound {
-		return receiver, resp.Header, resp.StatusCode, fmt.Errorf(NotFoundError)
-	}
-
-	if resp.StatusCode == http.StatusServiceUnavailable || resp.StatusCode == http.StatusBadGateway {
-		return receiver, resp.Header, resp.StatusCode, fmt.Errorf(ServiceUnavailableError)
+	if receiver == nil {
+		return receiver, resp.Header, resp.StatusCode, nil
 	}
 
 	err = json.NewDecoder(resp.Body).Decode(receiver)
@@ -40,44 +38,89 @@ func (oc *OpendaxClient) opendaxApiCall(endpoint string, receiver interface{}) (
 }
 
 func (oc *OpendaxClient) opendaxPostApiCall(endpoint string, body []byte, receiver interface{}) (interface{}, http.Header, int, error) {
-	uri := oc.platformUrl + endpoint
-
 	// TODO: Refactor to pass method into opendaxPostApiCall
-	method := "POST"
+	method := http.MethodPost
 	if endpoint == adminFinexSecretUpdateEndpoint {
-		method = "PUT"
+		method = http.MethodPut
 	}
 
-	req, err := http.NewRequest(method, uri, bytes.NewReader(body))
+	resp, err := oc.send(method, endpoint, body)
 	if err != nil {
-		panic(err)
+		return receiver, responseHeader(resp), responseStatus(resp), err
 	}
+	defer resp.Body.Close()
 
-	oc.SignRequest(req)
-
-	client := &http.Client{}
-
-	resp, err := client.Do(req)
-	if err != nil {
-		fmt.Printf("The HTTP request failed with error %s\n", err)
-		return receiver, http.Header{}, 0, fmt.Errorf(HttpTransportError)
+	if receiver == nil {
+		return receiver, resp.Header, resp.StatusCode, nil
 	}
-	defer resp.Body.Close()
 
-	fmt.Println("Response status:", resp.StatusCode)
+	err = json.NewDecoder(resp.Body).Decode(receiver)
+	return receiver, resp.Header, resp.StatusCode, err
+}
 
-	if resp.StatusCode == http.StatusNotFound {
-		return receiver, resp.Header, resp.StatusCode, fmt.Errorf(NotFoundError)
-	}
+// send sends the request, signed when it has a body, and sends it again after a backoff on transport and server errors.
+// Writes are not sent again after a transport error, the server may have applied them before the connection failed.
+// Non-2xx responses are returned as *HTTPError, along with the response whose body is already closed.
+func (oc *OpendaxClient) send(method, endpoint string, body []byte) (*http.Response, error) {
+	uri := oc.platformUrl + endpoint
 
-	if resp.StatusCode == http.StatusUnauthorized {
-		return receiver, resp.Header, resp.StatusCode, fmt.Errorf(NotAuthorizedError)
+	for retry := 0; ; retry++ {
+		if retry > 0 {
+			delay := oc.retry.Backoff(retry)
+			fmt.Fprintf(os.Stderr, "Retry %d/%d of %s %s in %s\n", retry, oc.retry.MaxRetries, method, endpoint, delay.Round(time.Millisecond))
+			time.Sleep(delay)
+		}
+
+		req, err := http.NewRequest(method, uri, bytes.NewReader(body))
+		if err != nil {
+			panic(err)
+		}
+
+		if method != http.MethodGet {
+			oc.SignRequest(req)
+		}
+
+		resp, err := oc.client.Do(req)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "The HTTP request failed with error %s\n", err)
+			if method == http.MethodGet && retry < oc.retry.MaxRetries {
+				continue
+			}
+			return nil, &TransportError{Endpoint: endpoint, Err: err}
+		}
+
+		if method != http.MethodGet {
+			fmt.Fprintln(os.Stderr, "Response status:", resp.StatusCode)
+		}
+
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return resp, nil
+		}
+
+		responseBody, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		httpErr := newHTTPError(resp.StatusCode, endpoint, responseBody)
+
+		// A create sent twice may list the market twice, it is never sent again
+		if endpoint != adminMarketsCreateEndpoint && helpers.RetryableStatus(method, resp.StatusCode) && retry < oc.retry.MaxRetries {
+			fmt.Fprintln(os.Stderr, httpErr)
+			continue
+		}
+
+		return resp, httpErr
 	}
+}
 
-	if resp.StatusCode == http.StatusServiceUnavailable || resp.StatusCode == http.StatusBadGateway {
-		return receiver, resp.Header, resp.StatusCode, fmt.Errorf(ServiceUnavailableError)
+func responseHeader(resp *http.Response) http.Header {
+	if resp == nil {
+		return http.Header{}
 	}
+	return resp.Header
+}
 
-	err = json.NewDecoder(resp.Body).Decode(receiver)
-	return receiver, resp.Header, resp.StatusCode, err
+func responseStatus(resp *http.Response) int {
+	if resp == nil {
+		return 0
+	}
+	return resp.StatusCode
 }
@@ -0,0 +1,139 @@
+package opendax
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severities of a market field difference
+const (
+	// SeverityCritical changes how orders are rounded
+	SeverityCritical = "critical"
+	// SeverityWarning changes which orders are accepted
+	SeverityWarning = "warning"
+	// SeverityInfo changes limits Binance rarely hits
+	SeverityInfo = "info"
+)
+
+// MarketFields lists the fields of a market which can be compared, in display order
+var MarketFields = []string{"min_price", "max_price", "min_amount", "max_amount", "amount_precision", "price_precision"}
+
+var marketFieldSeverities = map[string]string{
+	"min_price":        SeverityWarning,
+	"max_price":        SeverityInfo,
+	"min_amount":       SeverityWarning,
+	"max_amount":       SeverityInfo,
+	"amount_precision": SeverityCritical,
+	"price_precision":  SeverityCritical,
+}
+
+// FieldDiff is a market field whose current value differs from the target one
+type FieldDiff struct {
+	Field    string `json:"field"`
+	Old      string `json:"old"`
+	New      string `json:"new"`
+	Severity string `json:"severity"`
+}
+
+func (d FieldDiff) String() string {
+	return fmt.Sprintf("%s: %s -> %s", d.Field, d.Old, d.New)
+}
+
+// MarketDiff lists the differing fields of two markets, empty when they are equal
+type MarketDiff []FieldDiff
+
+func (d MarketDiff) String() string {
+	diffs := make([]string, len(d))
+	for i, fieldDiff := range d {
+		diffs[i] = fieldDiff.String()
+	}
+	return strings.Join(diffs, "; ")
+}
+
+// ValidateMarketFields checks that every field can be compared
+func ValidateMarketFields(fields []string) error {
+	for _, field := range fields {
+		if _, ok := marketFieldSeverities[field]; !ok {
+			return fmt.Errorf("unknown market field %q, expected one of %s", field, strings.Join(MarketFields, ", "))
+		}
+	}
+	return nil
+}
+
+// DiffOpendaxMarkets compares the given fields of the current market with the target one, every field when none is given
+func DiffOpendaxMarkets(current, target *OpendaxMarket, fields []string) MarketDiff {
+	if len(fields) == 0 {
+		fields = MarketFields
+	}
+
+	selected := make(map[string]bool)
+	for _, field := range fields {
+		selected[field] = true
+	}
+
+	diff := MarketDiff{}
+	for _, field := range MarketFields {
+		if !selected[field] {
+			continue
+		}
+
+		oldValue, newValue := current.fieldValue(field), target.fieldValue(field)
+		if oldValue != newValue {
+			diff = append(diff, FieldDiff{
+				Field:    field,
+				Old:      oldValue,
+				New:      newValue,
+				Severity: marketFieldSeverities[field],
+			})
+		}
+	}
+
+	return diff
+}
+
+// MergeOpendaxMarkets returns the current market with the given fields taken from the target one, every field when none is given
+func MergeOpendaxMarkets(current, target *OpendaxMarket, fields []string) OpendaxMarket {
+	if len(fields) == 0 {
+		fields = MarketFields
+	}
+
+	merged := *current
+	for _, field := range fields {
+		switch field {
+		case "min_price":
+			merged.MinPrice = target.MinPrice
+		case "max_price":
+			merged.MaxPrice = target.MaxPrice
+		case "min_amount":
+			merged.MinAmount = target.MinAmount
+		case "max_amount":
+			merged.MaxAmount = target.MaxAmount
+		case "amount_precision":
+			merged.AmountPrecision = target.AmountPrecision
+		case "price_precision":
+			merged.PricePrecision = target.PricePrecision
+		}
+	}
+
+	return merged
+}
+
+// fieldValue returns the normalized value of a market field, so 0.10 and 0.1 compare equal
+func (om *OpendaxMarket) fieldValue(field string) string {
+	switch field {
+	case "min_price":
+		return om.MinPrice.String()
+	case "max_price":
+		return om.MaxPrice.String()
+	case "min_amount":
+		return om.MinAmount.String()
+	case "max_amount":
+		return om.MaxAmount.String()
+	case "amount_precision":
+		return fmt.Sprint(om.AmountPrecision)
+	case "price_precision":
+		return fmt.Sprint(om.PricePrecision)
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,82 @@
+package opendax
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffOpendaxMarkets(t *testing.T) {
+	current := &OpendaxMarket{
+		Symbol:          "ethusdt",
+		MinPrice:        decimal.RequireFromString("0.01"),
+		MaxPrice:        decimal.Zero,
+		MinAmount:       decimal.RequireFromString("0.0030"),
+		AmountPrecision: 4,
+		PricePrecision:  2,
+	}
+
+	t.Run("equal markets", func(t *testing.T) {
+		target := *current
+		target.MinAmount = decimal.RequireFromString("0.003")
+		assert.Empty(t, DiffOpendaxMarkets(current, &target, nil))
+	})
+
+	target := *current
+	target.MinPrice = decimal.RequireFromString("0.1")
+	target.MinAmount = decimal.RequireFromString("0.004")
+	target.PricePrecision = 1
+
+	t.Run("every field", func(t *testing.T) {
+		diff := DiffOpendaxMarkets(current, &target, nil)
+		assert.Equal(t, MarketDiff{
+			{Field: "min_price", Old: "0.01", New: "0.1", Severity: SeverityWarning},
+			{Field: "min_amount", Old: "0.003", New: "0.004", Severity: SeverityWarning},
+			{Field: "price_precision", Old: "2", New: "1", Severity: SeverityCritical},
+		}, diff)
+		assert.Equal(t, "min_price: 0.01 -> 0.1; min_amount: 0.003 -> 0.004; price_precision: 2 -> 1", diff.String())
+	})
+
+	t.Run("selected fields", func(t *testing.T) {
+		diff := DiffOpendaxMarkets(current, &target, []string{"price_precision", "amount_precision"})
+		assert.Equal(t, MarketDiff{
+			{Field: "price_precision", Old: "2", New: "1", Severity: SeverityCritical},
+		}, diff)
+	})
+}
+
+func TestMergeOpendaxMarkets(t *testing.T) {
+	current := &OpendaxMarket{
+		Symbol:          "ethusdt",
+		MinPrice:        decimal.RequireFromString("0.01"),
+		MaxPrice:        decimal.RequireFromString("100000"),
+		MinAmount:       decimal.RequireFromString("0.003"),
+		MaxAmount:       decimal.RequireFromString("1000"),
+		AmountPrecision: 4,
+		PricePrecision:  2,
+	}
+	target := &OpendaxMarket{
+		Symbol:          "ethusdt",
+		MinPrice:        decimal.RequireFromString("0.1"),
+		MaxPrice:        decimal.RequireFromString("50000"),
+		MinAmount:       decimal.RequireFromString("0.004"),
+		MaxAmount:       decimal.RequireFromString("9000"),
+		AmountPrecision: 3,
+		PricePrecision:  1,
+	}
+
+	assert.Equal(t, *target, MergeOpendaxMarkets(current, target, nil))
+
+	merged := MergeOpendaxMarkets(current, target, []string{"min_amount"})
+	expected := *current
+	expected.MinAmount = target.MinAmount
+	assert.Equal(t, expected, merged)
+	assert.Equal(t, "0.003", current.MinAmount.String(), "current market is left untouched")
+}
+
+func TestValidateMarketFields(t *testing.T) {
+	assert.NoError(t, ValidateMarketFields([]string{"min_amount", "max_price"}))
+	assert.EqualError(t, ValidateMarketFields([]string{"min_amount", "tick_size"}),
+		`unknown market field "tick_size", expected one of min_price, max_price, min_amount, max_amount, amount_precision, price_precision`)
+}
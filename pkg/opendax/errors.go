@@ -0,0 +1,44 @@
+package opendax
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/openware/binance-cli/pkg/helpers"
+)
+
+// APIError lists the keys Peatio and Barong reject a request with, like admin.market.min_amount_invalid
+type APIError struct {
+	Errors []string `json:"errors"`
+}
+
+func (e *APIError) Error() string {
+	return strings.Join(e.Errors, ", ")
+}
+
+// Has tells whether the response lists the error key
+func (e *APIError) Has(key string) bool {
+	for _, k := range e.Errors {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// HTTPError is an OpenDAX response with a non-2xx status, its APIError is a *APIError when Peatio or Barong listed error keys
+type HTTPError = helpers.HTTPError
+
+func newHTTPError(statusCode int, endpoint string, body []byte) *HTTPError {
+	httpErr := &HTTPError{StatusCode: statusCode, Endpoint: endpoint, Body: string(body)}
+
+	apiErr := &APIError{}
+	if err := json.Unmarshal(body, apiErr); err == nil && len(apiErr.Errors) > 0 {
+		httpErr.APIError = apiErr
+	}
+
+	return httpErr
+}
+
+// TransportError is a request to OpenDAX which got no response, writes may have been applied anyway
+type TransportError = helpers.TransportError
@@ -0,0 +1,144 @@
+package opendax
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/openware/binance-cli/pkg/helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpendaxErrors(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch r.URL.Path {
+		case marketsEndpoint:
+			if calls == 1 {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			fmt.Fprint(w, `[{"symbol":"ethusdt"}]`)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `{"errors":["server.internal_error"]}`)
+		}
+	}))
+	defer server.Close()
+
+	client := NewOpendaxClient(server.URL)
+	client.SetRetryPolicy(helpers.RetryPolicy{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+
+	t.Run("reads are retried on server errors", func(t *testing.T) {
+		markets, err := client.FetchOpendaxMarkets()
+		require.NoError(t, err)
+		assert.Equal(t, 1, len(markets))
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("writes are not retried on internal errors", func(t *testing.T) {
+		calls = 0
+		_, err := client.UpdateOpendaxMarket(UpdateMarketRequest{Symbol: "ethusdt"})
+
+		var httpErr *HTTPError
+		require.True(t, errors.As(err, &httpErr))
+		assert.Equal(t, http.StatusInternalServerError, httpErr.StatusCode)
+		assert.Equal(t, adminMarketsUpdateEndpoint, httpErr.Endpoint)
+		assert.Equal(t, `{"errors":["server.internal_error"]}`, httpErr.Body)
+		assert.Equal(t, &APIError{Errors: []string{"server.internal_error"}}, httpErr.APIError)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("writes are not retried on transport errors", func(t *testing.T) {
+		calls := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			conn, _, err := w.(http.Hijacker).Hijack()
+			require.NoError(t, err)
+			conn.Close()
+		}))
+		defer server.Close()
+
+		client := NewOpendaxClient(server.URL)
+		client.SetRetryPolicy(helpers.RetryPolicy{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+
+		_, err := client.CreateOpendaxMarket(CreateMarketRequest{BaseCurrency: "eth", QuoteCurrency: "usdt"})
+
+		var transportErr *TransportError
+		require.True(t, errors.As(err, &transportErr))
+		assert.Equal(t, adminMarketsCreateEndpoint, transportErr.Endpoint)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("writes are only retried on unavailable servers", func(t *testing.T) {
+		status := http.StatusGatewayTimeout
+		calls := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(status)
+		}))
+		defer server.Close()
+
+		client := NewOpendaxClient(server.URL)
+		client.SetRetryPolicy(helpers.RetryPolicy{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+
+		// OpenDAX may still process a write timed out by the gateway
+		_, err := client.UpdateOpendaxMarket(UpdateMarketRequest{Symbol: "ethusdt"})
+		var httpErr *HTTPError
+		require.True(t, errors.As(err, &httpErr))
+		assert.Equal(t, http.StatusGatewayTimeout, httpErr.StatusCode)
+		assert.Equal(t, 1, calls)
+
+		status = http.StatusServiceUnavailable
+		calls = 0
+		_, err = client.UpdateOpendaxMarket(UpdateMarketRequest{Symbol: "ethusdt"})
+		require.Error(t, err)
+		assert.Equal(t, 3, calls)
+
+		calls = 0
+		_, err = client.CreateOpendaxMarket(CreateMarketRequest{BaseCurrency: "eth", QuoteCurrency: "usdt"})
+		require.Error(t, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("transport errors", func(t *testing.T) {
+		client := NewOpendaxClient("http://127.0.0.1:1")
+		client.SetRetryPolicy(helpers.RetryPolicy{})
+
+		_, err := client.FetchOpendaxCurrencies()
+
+		var transportErr *TransportError
+		require.True(t, errors.As(err, &transportErr))
+		assert.Equal(t, currenciesEndpoint, transportErr.Endpoint)
+	})
+}
+
+func TestOpendaxAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		fmt.Fprint(w, `{"errors":["admin.market.min_amount_invalid","admin.market.max_price_invalid"]}`)
+	}))
+	defer server.Close()
+
+	client := NewOpendaxClient(server.URL)
+	client.SetRetryPolicy(helpers.RetryPolicy{})
+
+	market, err := client.UpdateOpendaxMarket(UpdateMarketRequest{Symbol: "ethusdt"})
+	assert.Equal(t, OpendaxMarket{}, market)
+
+	var apiErr *APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, []string{"admin.market.min_amount_invalid", "admin.market.max_price_invalid"}, apiErr.Errors)
+	assert.True(t, apiErr.Has("admin.market.min_amount_invalid"))
+	assert.False(t, apiErr.Has("admin.market.not_found"))
+	assert.EqualError(t, err, adminMarketsUpdateEndpoint+": 422 Unprocessable Entity: admin.market.min_amount_invalid, admin.market.max_price_invalid")
+
+	plainErr := newHTTPError(http.StatusBadGateway, marketsEndpoint, []byte("<html>Bad Gateway</html>"))
+	assert.Nil(t, plainErr.APIError)
+	assert.False(t, errors.As(plainErr, &apiErr))
+}
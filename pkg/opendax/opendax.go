@@ -5,17 +5,34 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 	"time"
+
+	"github.com/openware/binance-cli/pkg/helpers"
 )
 
 func NewOpendaxClient(platformUrl string) *OpendaxClient {
 	return &OpendaxClient{
 		platformUrl: platformUrl,
+		client:      &http.Client{},
+		retry:       helpers.DefaultRetryPolicy,
 	}
 }
 
+// SetRetryPolicy defines how requests failing with a transport or server error are sent again
+func (oc *OpendaxClient) SetRetryPolicy(policy helpers.RetryPolicy) {
+	oc.retry = policy
+}
+
+// DryRun makes the client log write requests instead of sending them, read requests are still performed
+func (oc *OpendaxClient) DryRun(out io.Writer) *DryRunTransport {
+	transport := NewDryRunTransport(http.DefaultTransport, out)
+	oc.client.Transport = transport
+	return transport
+}
+
 func (oc *OpendaxClient) Authorize(apiKey, secretKey string) {
 	oc.apiKey = apiKey
 	oc.secretKey = secretKey
@@ -44,6 +61,28 @@ func (oc *OpendaxClient) UpdateOpendaxMarket(request UpdateMarketRequest) (Opend
 	return market, err
 }
 
+func (oc *OpendaxClient) CreateOpendaxMarket(request CreateMarketRequest) (OpendaxMarket, error) {
+	body, err := request.Encode()
+	if err != nil {
+		panic(err)
+	}
+
+	market := OpendaxMarket{}
+	_, _, _, err = oc.opendaxPostApiCall(adminMarketsCreateEndpoint, body, &market)
+	return market, err
+}
+
+func (oc *OpendaxClient) UpdateOpendaxCurrency(request UpdateCurrencyRequest) (OpendaxCurrency, error) {
+	body, err := request.Encode()
+	if err != nil {
+		panic(err)
+	}
+
+	currency := OpendaxCurrency{}
+	_, _, _, err = oc.opendaxPostApiCall(adminCurrenciesUpdateEndpoint, body, &currency)
+	return currency, err
+}
+
 func (oc *OpendaxClient) UpdateOpendaxSecret(request UpdateSecretRequest) error {
 	body, err := request.Encode()
 	if err != nil {
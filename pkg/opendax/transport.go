@@ -0,0 +1,81 @@
+package opendax
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+)
+
+// DryRunTransport forwards read-only requests and records every other request instead of sending it
+type DryRunTransport struct {
+	next     http.RoundTripper
+	out      io.Writer
+	Requests []*http.Request
+}
+
+func NewDryRunTransport(next http.RoundTripper, out io.Writer) *DryRunTransport {
+	return &DryRunTransport{
+		next: next,
+		out:  out,
+	}
+}
+
+func (t *DryRunTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return t.next.RoundTrip(req)
+	}
+
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	t.Requests = append(t.Requests, req)
+	t.log(req, body)
+
+	// Echo the request body back, so callers decode the values they would have set
+	return &http.Response{
+		Status:        "200 OK (dry run)",
+		StatusCode:    http.StatusOK,
+		Proto:         req.Proto,
+		ProtoMajor:    req.ProtoMajor,
+		ProtoMinor:    req.ProtoMinor,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// redactedHeaders are replaced in the log, the signature authenticates any request until the nonce expires
+var redactedHeaders = map[string]string{
+	"X-Auth-Signature": "<signed>",
+}
+
+func (t *DryRunTransport) log(req *http.Request, body []byte) {
+	fmt.Fprintf(t.out, "DRY RUN: %s %s\n", req.Method, req.URL)
+
+	keys := make([]string, 0, len(req.Header))
+	for k := range req.Header {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		for _, v := range req.Header[k] {
+			if redacted, ok := redactedHeaders[k]; ok {
+				v = redacted
+			}
+			fmt.Fprintf(t.out, "	%s: %s\n", k, v)
+		}
+	}
+	fmt.Fprintf(t.out, "	%s\n", body)
+}
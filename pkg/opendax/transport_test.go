@@ -0,0 +1,53 @@
+package opendax
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryRunTransport(t *testing.T) {
+	var sent []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent = append(sent, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `[{"symbol":"ethusdt","min_amount":"0.001"}]`)
+	}))
+	defer server.Close()
+
+	out := &bytes.Buffer{}
+	client := NewOpendaxClient(server.URL)
+	client.Authorize("key", "secret")
+	transport := client.DryRun(out)
+
+	markets, err := client.FetchOpendaxMarkets()
+	require.NoError(t, err)
+	assert.Equal(t, "ethusdt", markets[0].Symbol)
+
+	market, err := client.UpdateOpendaxMarket(UpdateMarketRequest{
+		Symbol:    "ethusdt",
+		MinAmount: decimal.RequireFromString("0.003"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "ethusdt", market.Symbol)
+	assert.True(t, decimal.RequireFromString("0.003").Equal(market.MinAmount))
+
+	require.NoError(t, client.UpdateOpendaxSecret(UpdateSecretRequest{Scope: "private", Key: "restart", Value: "now"}))
+
+	assert.Equal(t, []string{"GET " + marketsEndpoint}, sent)
+	require.Len(t, transport.Requests, 2)
+	assert.Equal(t, "POST", transport.Requests[0].Method)
+	assert.Equal(t, "key", transport.Requests[0].Header.Get("X-Auth-Apikey"))
+	assert.Equal(t, "PUT", transport.Requests[1].Method)
+	assert.Contains(t, out.String(), "DRY RUN: POST "+server.URL+adminMarketsUpdateEndpoint)
+	assert.Contains(t, out.String(), `"min_amount":"0.003"`)
+	assert.Contains(t, out.String(), "X-Auth-Apikey: key\n")
+	assert.Contains(t, out.String(), "X-Auth-Signature: <signed>\n")
+	assert.NotContains(t, out.String(), transport.Requests[0].Header.Get("X-Auth-Signature"))
+}
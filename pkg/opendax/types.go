@@ -3,8 +3,12 @@ package opendax
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"os"
 	"strings"
 
+	"github.com/openware/binance-cli/pkg/helpers"
 	"github.com/shopspring/decimal"
 )
 
@@ -12,12 +16,15 @@ type OpendaxClient struct {
 	platformUrl string
 	apiKey      string
 	secretKey   string
+	client      *http.Client
+	retry       helpers.RetryPolicy
 }
 
 type OpendaxCurrencies []*OpendaxCurrency
 
 type OpendaxCurrency struct {
-	Code              string      `json:"id"`
+	Code              string          `json:"id"`
+	Status            string          `json:"status"`
 	WithdrawFee       decimal.Decimal `json:"withdraw_fee"`
 	MinWithdrawAmount decimal.Decimal `json:"min_withdraw_amount"`
 }
@@ -26,6 +33,13 @@ func (c *OpendaxCurrency) ToBinanceCoinName() string {
 	return strings.ToUpper(c.Code)
 }
 
+// Enabled reports whether the currency is enabled, Peatio versions without currency status only list enabled ones
+func (c *OpendaxCurrency) Enabled() bool {
+	return c.Status == "" || c.Status == "enabled"
+}
+
+const MarketStateDisabled = "disabled"
+
 type OpendaxMarkets []OpendaxMarket
 
 type OpendaxMarket struct {
@@ -33,9 +47,11 @@ type OpendaxMarket struct {
 	Name            string          `json:"name"`
 	BaseUnit        string          `json:"base_unit"`
 	QuoteUnit       string          `json:"quote_unit"`
+	State           string          `json:"state,omitempty"`
 	MinPrice        decimal.Decimal `json:"min_price"`
 	MaxPrice        decimal.Decimal `json:"max_price"`
 	MinAmount       decimal.Decimal `json:"min_amount"`
+	MaxAmount       decimal.Decimal `json:"max_amount"`
 	AmountPrecision int64           `json:"amount_precision"`
 	PricePrecision  int64           `json:"price_precision"`
 }
@@ -45,16 +61,21 @@ func (om OpendaxMarket) ToBinanceMarketName() string {
 }
 
 func (om OpendaxMarket) Print() {
-	fmt.Println("- 	Symbol:", om.Symbol)
-	fmt.Println("	Name:", om.Name)
-	fmt.Println("	BaseUnit:", om.BaseUnit)
-	fmt.Println("	QuoteUnit:", om.QuoteUnit)
-	fmt.Println("	MinPrice:", om.MinPrice)
-	fmt.Println("	MaxPrice:", om.MaxPrice)
-	fmt.Println("	MinAmount:", om.MinAmount)
-	fmt.Println("	AmountPrecision:", om.AmountPrecision)
-	fmt.Println("	PricePrecision:", om.PricePrecision)
-	fmt.Println("")
+	om.Fprint(os.Stdout)
+}
+
+func (om OpendaxMarket) Fprint(w io.Writer) {
+	fmt.Fprintln(w, "- 	Symbol:", om.Symbol)
+	fmt.Fprintln(w, "	Name:", om.Name)
+	fmt.Fprintln(w, "	BaseUnit:", om.BaseUnit)
+	fmt.Fprintln(w, "	QuoteUnit:", om.QuoteUnit)
+	fmt.Fprintln(w, "	MinPrice:", om.MinPrice)
+	fmt.Fprintln(w, "	MaxPrice:", om.MaxPrice)
+	fmt.Fprintln(w, "	MinAmount:", om.MinAmount)
+	fmt.Fprintln(w, "	MaxAmount:", om.MaxAmount)
+	fmt.Fprintln(w, "	AmountPrecision:", om.AmountPrecision)
+	fmt.Fprintln(w, "	PricePrecision:", om.PricePrecision)
+	fmt.Fprintln(w, "")
 }
 
 type Request interface {
@@ -62,23 +83,48 @@ type Request interface {
 }
 
 type UpdateMarketRequest struct {
-	Symbol          string          `json:"symbol"`
-	MinPrice        decimal.Decimal `json:"min_price"`
-	MaxPrice        decimal.Decimal `json:"max_price"`
-	MinAmount       decimal.Decimal `json:"min_amount"`
-	AmountPrecision int64             `json:"amount_precision"`
-	PricePrecision  int64             `json:"price_precision"`
+	Symbol          string           `json:"symbol"`
+	State           string           `json:"state,omitempty"`
+	MinPrice        decimal.Decimal  `json:"min_price"`
+	MaxPrice        decimal.Decimal  `json:"max_price"`
+	MinAmount       decimal.Decimal  `json:"min_amount"`
+	MaxAmount       *decimal.Decimal `json:"max_amount,omitempty"`
+	AmountPrecision int64            `json:"amount_precision"`
+	PricePrecision  int64            `json:"price_precision"`
 }
 
 func (r *UpdateMarketRequest) Encode() ([]byte, error) {
 	return json.Marshal(r)
 }
 
-func CompareOpendaxMarkets(firstMarket, secondMarket *OpendaxMarket) bool {
-	if firstMarket.AmountPrecision != secondMarket.AmountPrecision || firstMarket.PricePrecision != secondMarket.PricePrecision || !firstMarket.MinAmount.Equals(secondMarket.MinAmount) {
-		return false
-	}
-	return true
+// CreateMarketRequest represents params for a Peatio admin market creation request
+type CreateMarketRequest struct {
+	BaseCurrency    string           `json:"base_currency"`
+	QuoteCurrency   string           `json:"quote_currency"`
+	EngineId        int64            `json:"engine_id"`
+	State           string           `json:"state"`
+	Position        int64            `json:"position,omitempty"`
+	MinPrice        decimal.Decimal  `json:"min_price"`
+	MaxPrice        decimal.Decimal  `json:"max_price"`
+	MinAmount       decimal.Decimal  `json:"min_amount"`
+	MaxAmount       *decimal.Decimal `json:"max_amount,omitempty"`
+	AmountPrecision int64            `json:"amount_precision"`
+	PricePrecision  int64            `json:"price_precision"`
+}
+
+func (r *CreateMarketRequest) Encode() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+// UpdateCurrencyRequest represents params for a Peatio admin currency update request
+type UpdateCurrencyRequest struct {
+	Code              string          `json:"code"`
+	WithdrawFee       decimal.Decimal `json:"withdraw_fee"`
+	MinWithdrawAmount decimal.Decimal `json:"min_withdraw_amount"`
+}
+
+func (r *UpdateCurrencyRequest) Encode() ([]byte, error) {
+	return json.Marshal(r)
 }
 
 // UpdateSecretRequest represents params for a Sonic secret update request
@@ -0,0 +1,68 @@
+package policy
+
+import (
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+var hundred = decimal.NewFromInt(100)
+
+// FeePolicy derives the OpenDAX withdraw fee and min withdraw amount from the Binance ones.
+// Unset fields of a currency override are inherited from the default policy.
+type FeePolicy struct {
+	// Markup is the percentage charged on top of the Binance value
+	Markup *decimal.Decimal `yaml:"markup" json:"markup"`
+	// Floor is the lowest value to charge, whatever the Binance value is
+	Floor *decimal.Decimal `yaml:"floor" json:"floor"`
+	// Precision is the number of decimal places the target is rounded up to
+	Precision *int32 `yaml:"precision" json:"precision"`
+}
+
+// FeePolicies holds the default fee policy and the per currency overrides, keyed by OpenDAX currency id
+type FeePolicies struct {
+	Default    FeePolicy            `yaml:"default" json:"default"`
+	Currencies map[string]FeePolicy `yaml:"currencies" json:"currencies"`
+}
+
+// For returns the policy of an OpenDAX currency, merged with the default policy
+func (p FeePolicies) For(currency string) FeePolicy {
+	policy := p.Default
+
+	override, ok := p.Currencies[strings.ToLower(currency)]
+	if !ok {
+		return policy
+	}
+
+	if override.Markup != nil {
+		policy.Markup = override.Markup
+	}
+	if override.Floor != nil {
+		policy.Floor = override.Floor
+	}
+	if override.Precision != nil {
+		policy.Precision = override.Precision
+	}
+
+	return policy
+}
+
+// Target returns the value OpenDAX should charge for a Binance value
+func (p FeePolicy) Target(binanceValue decimal.Decimal) decimal.Decimal {
+	target := binanceValue
+
+	if p.Markup != nil {
+		target = target.Add(target.Mul(*p.Markup).Div(hundred))
+	}
+
+	if p.Floor != nil && target.LessThan(*p.Floor) {
+		target = *p.Floor
+	}
+
+	if p.Precision != nil {
+		// Round up, so the rounding never eats into the markup
+		target = target.Shift(*p.Precision).Ceil().Shift(-*p.Precision)
+	}
+
+	return target
+}
@@ -0,0 +1,61 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func decimalPtr(value string) *decimal.Decimal {
+	d := decimal.RequireFromString(value)
+	return &d
+}
+
+func int32Ptr(value int32) *int32 {
+	return &value
+}
+
+func TestFeePolicyTarget(t *testing.T) {
+	t.Run("empty policy matches Binance", func(t *testing.T) {
+		assert.Equal(t, "0.0005", FeePolicy{}.Target(decimal.RequireFromString("0.0005")).String())
+	})
+
+	t.Run("markup", func(t *testing.T) {
+		policy := FeePolicy{Markup: decimalPtr("10")}
+		assert.Equal(t, "0.00055", policy.Target(decimal.RequireFromString("0.0005")).String())
+	})
+
+	t.Run("floor", func(t *testing.T) {
+		policy := FeePolicy{Markup: decimalPtr("10"), Floor: decimalPtr("2")}
+		assert.Equal(t, "2", policy.Target(decimal.RequireFromString("1")).String())
+		assert.Equal(t, "3.3", policy.Target(decimal.RequireFromString("3")).String())
+	})
+
+	t.Run("precision rounds up", func(t *testing.T) {
+		policy := FeePolicy{Markup: decimalPtr("15"), Precision: int32Ptr(4)}
+		assert.Equal(t, "0.0006", policy.Target(decimal.RequireFromString("0.0005")).String())
+		assert.Equal(t, "1.15", policy.Target(decimal.RequireFromString("1")).String())
+	})
+}
+
+func TestFeePoliciesFor(t *testing.T) {
+	policies := FeePolicies{
+		Default: FeePolicy{Markup: decimalPtr("10"), Precision: int32Ptr(8)},
+		Currencies: map[string]FeePolicy{
+			"usdt": {Floor: decimalPtr("2")},
+			"btc":  {Markup: decimalPtr("5")},
+		},
+	}
+
+	usdt := policies.For("USDT")
+	assert.Equal(t, "10", usdt.Markup.String())
+	assert.Equal(t, "2", usdt.Floor.String())
+	assert.Equal(t, int32(8), *usdt.Precision)
+
+	btc := policies.For("btc")
+	assert.Equal(t, "5", btc.Markup.String())
+	assert.Nil(t, btc.Floor)
+
+	assert.Equal(t, policies.Default, policies.For("eth"))
+}
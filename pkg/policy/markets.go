@@ -0,0 +1,130 @@
+package policy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/openware/binance-cli/pkg/opendax"
+	"github.com/shopspring/decimal"
+)
+
+var niceSteps = []decimal.Decimal{decimal.NewFromInt(1), decimal.NewFromInt(2), decimal.NewFromInt(5), decimal.NewFromInt(10)}
+
+// MarketPolicy decides which limits derived from Binance are synced to OpenDAX markets.
+// Limits which are not enforced are only reported, the OpenDAX market keeps its own.
+type MarketPolicy struct {
+	// EnforceMaxPrice syncs the max price derived from PRICE_FILTER and PERCENT_PRICE
+	EnforceMaxPrice bool `yaml:"enforce_max_price" json:"enforce_max_price"`
+	// EnforceMaxAmount syncs the max amount derived from LOT_SIZE
+	EnforceMaxAmount bool `yaml:"enforce_max_amount" json:"enforce_max_amount"`
+	// Fields are the market fields compared with Binance, every field when empty
+	Fields []string `yaml:"fields" json:"fields"`
+	// MinAmountTolerance is the drift in percent of the target min amount the OpenDAX one may have before it is updated
+	MinAmountTolerance *decimal.Decimal `yaml:"min_amount_tolerance" json:"min_amount_tolerance"`
+	// NiceMinAmount rounds the target min amount up to 1, 2 or 5 times a power of ten
+	NiceMinAmount bool `yaml:"nice_min_amount" json:"nice_min_amount"`
+	// MinNotional defines the cushion on top of the Binance min notional the min amount is derived from
+	MinNotional NotionalPolicies `yaml:"min_notional" json:"min_notional"`
+}
+
+// NotionalPolicy defines the notional in quote unit the OpenDAX min amount must cover.
+// Unset fields of an override are inherited from the less specific policy.
+type NotionalPolicy struct {
+	// Multiplier is applied to the Binance min notional, 1.05 by default
+	Multiplier *decimal.Decimal `yaml:"multiplier" json:"multiplier"`
+	// Floor is the lowest notional in quote unit, whatever the Binance min notional is
+	Floor *decimal.Decimal `yaml:"floor" json:"floor"`
+}
+
+// NotionalPolicies holds the default notional policy and the overrides per OpenDAX quote unit and market symbol,
+// a market override takes precedence over a quote unit one
+type NotionalPolicies struct {
+	Default    NotionalPolicy            `yaml:"default" json:"default"`
+	QuoteUnits map[string]NotionalPolicy `yaml:"quote_units" json:"quote_units"`
+	Markets    map[string]NotionalPolicy `yaml:"markets" json:"markets"`
+}
+
+// For returns the policy of an OpenDAX market, merged with the quote unit and default policies
+func (p NotionalPolicies) For(market, quoteUnit string) NotionalPolicy {
+	policy := p.Default
+
+	for _, override := range []NotionalPolicy{p.QuoteUnits[strings.ToLower(quoteUnit)], p.Markets[strings.ToLower(market)]} {
+		if override.Multiplier != nil {
+			policy.Multiplier = override.Multiplier
+		}
+		if override.Floor != nil {
+			policy.Floor = override.Floor
+		}
+	}
+
+	return policy
+}
+
+// MultiplierOr returns the multiplier, or the given default when unset
+func (p NotionalPolicy) MultiplierOr(defaultMultiplier decimal.Decimal) decimal.Decimal {
+	if p.Multiplier == nil {
+		return defaultMultiplier
+	}
+	return *p.Multiplier
+}
+
+// FloorOrZero returns the floor, zero when unset
+func (p NotionalPolicy) FloorOrZero() decimal.Decimal {
+	if p.Floor == nil {
+		return decimal.Zero
+	}
+	return *p.Floor
+}
+
+// Apply resets the limits of the target which are not enforced to the current ones,
+// and returns a report of the Binance limits left out
+func (p MarketPolicy) Apply(current opendax.OpendaxMarket, target *opendax.OpendaxMarket) []string {
+	var unenforced []string
+
+	if p.NiceMinAmount {
+		target.MinAmount = niceCeil(target.MinAmount)
+	}
+
+	if p.MinAmountTolerance != nil && !target.MinAmount.Equal(current.MinAmount) {
+		band := target.MinAmount.Mul(*p.MinAmountTolerance).Div(hundred)
+		if current.MinAmount.Sub(target.MinAmount).Abs().LessThanOrEqual(band) {
+			unenforced = append(unenforced, fmt.Sprintf("min_amount %s within %s%% of OpenDAX %s", target.MinAmount, p.MinAmountTolerance, current.MinAmount))
+			target.MinAmount = current.MinAmount
+		}
+	}
+
+	if !p.EnforceMaxPrice {
+		if !target.MaxPrice.Equal(current.MaxPrice) {
+			unenforced = append(unenforced, fmt.Sprintf("max_price %s (OpenDAX %s)", target.MaxPrice, current.MaxPrice))
+		}
+		target.MaxPrice = current.MaxPrice
+	}
+
+	if !p.EnforceMaxAmount {
+		if !target.MaxAmount.Equal(current.MaxAmount) {
+			unenforced = append(unenforced, fmt.Sprintf("max_amount %s (OpenDAX %s)", target.MaxAmount, current.MaxAmount))
+		}
+		target.MaxAmount = current.MaxAmount
+	}
+
+	return unenforced
+}
+
+// niceCeil rounds a positive value up to 1, 2 or 5 times a power of ten
+func niceCeil(value decimal.Decimal) decimal.Decimal {
+	if !value.IsPositive() {
+		return value
+	}
+
+	// value = mantissa * 10^magnitude, with a mantissa in [1, 10)
+	magnitude := int32(len(value.Coefficient().String())) - 1 + value.Exponent()
+	mantissa := value.Shift(-magnitude)
+
+	for _, step := range niceSteps {
+		if mantissa.LessThanOrEqual(step) {
+			return step.Shift(magnitude)
+		}
+	}
+
+	return value
+}
@@ -0,0 +1,140 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/openware/binance-cli/pkg/opendax"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarketPolicyApply(t *testing.T) {
+	current := opendax.OpendaxMarket{
+		MaxPrice:  decimal.Zero,
+		MaxAmount: decimal.Zero,
+	}
+
+	binanceTarget := func() *opendax.OpendaxMarket {
+		return &opendax.OpendaxMarket{
+			MaxPrice:  decimal.RequireFromString("175000"),
+			MaxAmount: decimal.RequireFromString("9000"),
+		}
+	}
+
+	t.Run("limits are reported by default", func(t *testing.T) {
+		target := binanceTarget()
+		unenforced := MarketPolicy{}.Apply(current, target)
+
+		assert.Equal(t, []string{"max_price 175000 (OpenDAX 0)", "max_amount 9000 (OpenDAX 0)"}, unenforced)
+		assert.Equal(t, "0", target.MaxPrice.String())
+		assert.Equal(t, "0", target.MaxAmount.String())
+	})
+
+	t.Run("enforced limits are kept", func(t *testing.T) {
+		target := binanceTarget()
+		unenforced := MarketPolicy{EnforceMaxPrice: true}.Apply(current, target)
+
+		assert.Equal(t, []string{"max_amount 9000 (OpenDAX 0)"}, unenforced)
+		assert.Equal(t, "175000", target.MaxPrice.String())
+		assert.Equal(t, "0", target.MaxAmount.String())
+	})
+
+	t.Run("nothing to report when limits match", func(t *testing.T) {
+		target := binanceTarget()
+		matching := *target
+		assert.Empty(t, MarketPolicy{}.Apply(matching, target))
+	})
+}
+
+func TestNiceCeil(t *testing.T) {
+	for value, expected := range map[string]string{
+		"0.0123": "0.02",
+		"0.02":   "0.02",
+		"0.021":  "0.05",
+		"3":      "5",
+		"0.6":    "1",
+		"7.5":    "10",
+		"150":    "200",
+		"0":      "0",
+	} {
+		assert.Equal(t, expected, niceCeil(decimal.RequireFromString(value)).String(), value)
+	}
+}
+
+func TestMarketPolicyMinAmount(t *testing.T) {
+	current := opendax.OpendaxMarket{MinAmount: decimal.RequireFromString("0.0030")}
+	enforceLimits := MarketPolicy{EnforceMaxPrice: true, EnforceMaxAmount: true}
+
+	t.Run("drift within tolerance keeps the OpenDAX min amount", func(t *testing.T) {
+		p := enforceLimits
+		p.MinAmountTolerance = decimalPtr("10")
+
+		target := &opendax.OpendaxMarket{MinAmount: decimal.RequireFromString("0.0032")}
+		unenforced := p.Apply(current, target)
+
+		assert.Equal(t, "0.003", target.MinAmount.String())
+		assert.Equal(t, []string{"min_amount 0.0032 within 10% of OpenDAX 0.003"}, unenforced)
+	})
+
+	t.Run("drift outside tolerance updates the min amount", func(t *testing.T) {
+		p := enforceLimits
+		p.MinAmountTolerance = decimalPtr("10")
+
+		target := &opendax.OpendaxMarket{MinAmount: decimal.RequireFromString("0.0034")}
+		assert.Empty(t, p.Apply(current, target))
+		assert.Equal(t, "0.0034", target.MinAmount.String())
+	})
+
+	t.Run("nice min amount", func(t *testing.T) {
+		p := enforceLimits
+		p.NiceMinAmount = true
+
+		target := &opendax.OpendaxMarket{MinAmount: decimal.RequireFromString("0.0032")}
+		assert.Empty(t, p.Apply(current, target))
+		assert.Equal(t, "0.005", target.MinAmount.String())
+	})
+
+	t.Run("nice min amount is stable within the band", func(t *testing.T) {
+		p := enforceLimits
+		p.NiceMinAmount = true
+		p.MinAmountTolerance = decimalPtr("0")
+
+		target := &opendax.OpendaxMarket{MinAmount: decimal.RequireFromString("0.0043")}
+		p.Apply(opendax.OpendaxMarket{MinAmount: decimal.RequireFromString("0.005")}, target)
+		assert.Equal(t, "0.005", target.MinAmount.String())
+	})
+}
+
+func TestNotionalPoliciesFor(t *testing.T) {
+	policies := NotionalPolicies{
+		Default: NotionalPolicy{Multiplier: decimalPtr("1.1")},
+		QuoteUnits: map[string]NotionalPolicy{
+			"usdt": {Multiplier: decimalPtr("1.2"), Floor: decimalPtr("10")},
+		},
+		Markets: map[string]NotionalPolicy{
+			"shibusdt": {Multiplier: decimalPtr("2")},
+		},
+	}
+
+	t.Run("default", func(t *testing.T) {
+		policy := policies.For("ethbtc", "btc")
+		assert.Equal(t, "1.1", policy.MultiplierOr(decimal.NewFromInt(1)).String())
+		assert.Equal(t, "0", policy.FloorOrZero().String())
+	})
+
+	t.Run("quote unit override", func(t *testing.T) {
+		policy := policies.For("ethusdt", "USDT")
+		assert.Equal(t, "1.2", policy.MultiplierOr(decimal.NewFromInt(1)).String())
+		assert.Equal(t, "10", policy.FloorOrZero().String())
+	})
+
+	t.Run("market override inherits the quote unit floor", func(t *testing.T) {
+		policy := policies.For("shibusdt", "usdt")
+		assert.Equal(t, "2", policy.MultiplierOr(decimal.NewFromInt(1)).String())
+		assert.Equal(t, "10", policy.FloorOrZero().String())
+	})
+
+	t.Run("unset multiplier", func(t *testing.T) {
+		assert.Equal(t, "1.05", NotionalPolicy{}.MultiplierOr(decimal.RequireFromString("1.05")).String())
+	})
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/openware/binance-cli/pkg/binance"
+	"github.com/openware/binance-cli/pkg/helpers"
+	"github.com/openware/binance-cli/pkg/opendax"
+)
+
+// DefaultPlanPath is used by `markets plan` and `markets apply` when no plan file is given
+const DefaultPlanPath = "markets-plan.json"
+
+// MarketsPlan is a reviewable set of market changes produced by `markets plan`
+type MarketsPlan struct {
+	PlatformUrl string         `json:"platform_url"`
+	CreatedAt   time.Time      `json:"created_at"`
+	Changes     []MarketChange `json:"changes"`
+}
+
+// MarketChange pairs the market state seen while planning with the update to apply
+type MarketChange struct {
+	Current opendax.OpendaxMarket       `json:"current"`
+	Request opendax.UpdateMarketRequest `json:"request"`
+}
+
+// Drifted reports whether the live market no longer matches the state the change was planned against
+func (c *MarketChange) Drifted(live opendax.OpendaxMarket) bool {
+	return !c.Current.MinPrice.Equal(live.MinPrice) ||
+		!c.Current.MaxPrice.Equal(live.MaxPrice) ||
+		!c.Current.MinAmount.Equal(live.MinAmount) ||
+		!c.Current.MaxAmount.Equal(live.MaxAmount) ||
+		c.Current.AmountPrecision != live.AmountPrecision ||
+		c.Current.PricePrecision != live.PricePrecision ||
+		c.Current.State != live.State
+}
+
+func planPath(args []string) string {
+	if len(args) > 0 {
+		return args[0]
+	}
+	return DefaultPlanPath
+}
+
+func planMarkets(args []string) error {
+	config := readConfig()
+
+	if err := binance.ValidatePriceSource(PriceSource); err != nil {
+		return err
+	}
+
+	fields, err := compareFields(config)
+	if err != nil {
+		return err
+	}
+
+	binanceClient := newBinanceClient(config, "", "")
+	binanceInfo, err := binanceClient.ExchangeInfo()
+	if err != nil {
+		return err
+	}
+
+	opendaxClient := newOpendaxClient(config)
+	opendaxMarkets, err := opendaxClient.FetchOpendaxMarkets()
+	if err != nil {
+		return err
+	}
+
+	plan := MarketsPlan{
+		PlatformUrl: config.PlatformBaseUrl,
+		CreatedAt:   time.Now().UTC(),
+		Changes:     []MarketChange{},
+	}
+
+	for _, opendaxMarket := range opendaxMarkets {
+		reference := config.Markets.For(opendaxMarket)
+		binanceMarket, ok := binanceInfo.MarketRegistry[reference.Symbol]
+		if !ok {
+			fmt.Println(opendaxMarket.Symbol, "is missing on Binance as", reference.Symbol)
+			continue
+		}
+
+		if !binanceMarket.IsTrading() {
+			fmt.Println(opendaxMarket.Symbol, "is not trading on Binance as", reference.Symbol, "status:", binanceMarket.Status)
+			continue
+		}
+
+		_, target, unenforced, err := binanceTargetMarket(binanceClient, binanceMarket, opendaxMarket, reference, config.MarketPolicy)
+		// Every remaining market would fail the same way, a partial plan is not worth writing
+		var banErr *binance.BanError
+		if errors.As(err, &banErr) {
+			return withBinanceHint(err)
+		}
+		if err != nil {
+			fmt.Printf("Error planning %s: %s\n", opendaxMarket.Symbol, err)
+			continue
+		}
+
+		for _, limit := range unenforced {
+			fmt.Println(opendaxMarket.Symbol, "Binance limit not enforced:", limit)
+		}
+
+		diff := opendax.DiffOpendaxMarkets(&opendaxMarket, target, fields)
+		if len(diff) == 0 {
+			continue
+		}
+
+		fmt.Println("Planning update for", opendaxMarket.Symbol, diff)
+		plan.Changes = append(plan.Changes, MarketChange{
+			Current: opendaxMarket,
+			Request: marketUpdateRequest(opendaxMarket, target, fields),
+		})
+	}
+
+	body, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := planPath(args)
+	if err := helpers.WriteToFile(path, string(body)); err != nil {
+		return err
+	}
+
+	fmt.Printf("Planned %d of %d OpenDAX markets, saved to %s\n", len(plan.Changes), len(opendaxMarkets), path)
+
+	return nil
+}
+
+func applyMarkets(args []string) error {
+	config := readConfig()
+
+	path := planPath(args)
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	plan := MarketsPlan{}
+	if err := json.Unmarshal(body, &plan); err != nil {
+		return fmt.Errorf("invalid plan %s: %w", path, err)
+	}
+
+	if plan.PlatformUrl != config.PlatformBaseUrl {
+		return fmt.Errorf("plan %s was created for %s, not %s", path, plan.PlatformUrl, config.PlatformBaseUrl)
+	}
+
+	opendaxClient := newAdminOpendaxClient(config)
+	opendaxMarkets, err := opendaxClient.FetchOpendaxMarkets()
+	if err != nil {
+		return err
+	}
+
+	liveMarkets := make(map[string]opendax.OpendaxMarket)
+	for _, m := range opendaxMarkets {
+		liveMarkets[m.Symbol] = m
+	}
+
+	summary := &marketsSummary{}
+
+	for _, change := range plan.Changes {
+		live, ok := liveMarkets[change.Request.Symbol]
+		if !ok {
+			fmt.Println(change.Request.Symbol, "no longer exists on OpenDAX, skipping")
+			summary.add(change.Request.Symbol, OutcomeMissing)
+			continue
+		}
+
+		if change.Drifted(live) {
+			fmt.Println(change.Request.Symbol, "has changed since the plan was created, skipping")
+			fmt.Println("Planned against:")
+			change.Current.Print()
+			fmt.Println("Live:")
+			live.Print()
+			summary.add(live.Name, OutcomeSkipped)
+			continue
+		}
+
+		updatedMarket, err := opendaxClient.UpdateOpendaxMarket(change.Request)
+		if err != nil {
+			fmt.Printf("Error updating %s: %s\n", change.Request.Symbol, err)
+			summary.add(live.Name, OutcomeFailed)
+			continue
+		}
+
+		fmt.Println("New market:")
+		updatedMarket.Print()
+
+		summary.add(live.Name, OutcomeUpdated)
+	}
+
+	updatedMarkets := summary.markets(OutcomeUpdated)
+	finalizeMarketUpdates(opendaxClient, updatedMarkets)
+
+	fmt.Printf("Applied %d of %d planned changes\n", len(updatedMarkets), len(plan.Changes))
+	summary.Fprint(os.Stdout)
+
+	return summary.Err()
+}
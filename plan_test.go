@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/openware/binance-cli/pkg/opendax"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var plannedMarket = opendax.OpendaxMarket{
+	Symbol:          "ethusdt",
+	Name:            "ETH/USDT",
+	BaseUnit:        "eth",
+	QuoteUnit:       "usdt",
+	State:           "enabled",
+	MinPrice:        decimal.RequireFromString("0.01"),
+	MaxPrice:        decimal.RequireFromString("100000"),
+	MinAmount:       decimal.RequireFromString("0.005"),
+	MaxAmount:       decimal.RequireFromString("9000"),
+	AmountPrecision: 4,
+	PricePrecision:  2,
+}
+
+func TestMarketChangeDrifted(t *testing.T) {
+	change := MarketChange{Current: plannedMarket}
+
+	tests := []struct {
+		name    string
+		live    func(m *opendax.OpendaxMarket)
+		drifted bool
+	}{
+		{"unchanged", func(m *opendax.OpendaxMarket) {}, false},
+		{"same value written differently", func(m *opendax.OpendaxMarket) { m.MinAmount = decimal.RequireFromString("0.0050") }, false},
+		{"min price", func(m *opendax.OpendaxMarket) { m.MinPrice = decimal.RequireFromString("0.1") }, true},
+		{"max price", func(m *opendax.OpendaxMarket) { m.MaxPrice = decimal.RequireFromString("50000") }, true},
+		{"min amount", func(m *opendax.OpendaxMarket) { m.MinAmount = decimal.RequireFromString("0.01") }, true},
+		{"max amount", func(m *opendax.OpendaxMarket) { m.MaxAmount = decimal.Zero }, true},
+		{"amount precision", func(m *opendax.OpendaxMarket) { m.AmountPrecision = 3 }, true},
+		{"price precision", func(m *opendax.OpendaxMarket) { m.PricePrecision = 3 }, true},
+		{"state", func(m *opendax.OpendaxMarket) { m.State = opendax.MarketStateDisabled }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			live := plannedMarket
+			tt.live(&live)
+			assert.Equal(t, tt.drifted, change.Drifted(live))
+		})
+	}
+}
+
+func TestMarketsPlanRoundTrip(t *testing.T) {
+	target := plannedMarket
+	target.MinAmount = decimal.RequireFromString("0.006")
+	target.AmountPrecision = 3
+
+	plan := MarketsPlan{
+		PlatformUrl: "https://example.com",
+		CreatedAt:   time.Date(2021, 8, 1, 12, 0, 0, 0, time.UTC),
+		Changes: []MarketChange{
+			{Current: plannedMarket, Request: marketUpdateRequest(plannedMarket, &target, nil)},
+		},
+	}
+
+	body, err := json.MarshalIndent(plan, "", "  ")
+	require.NoError(t, err)
+
+	loaded := MarketsPlan{}
+	require.NoError(t, json.Unmarshal(body, &loaded))
+
+	assert.Equal(t, plan.PlatformUrl, loaded.PlatformUrl)
+	assert.True(t, plan.CreatedAt.Equal(loaded.CreatedAt))
+	require.Equal(t, 1, len(loaded.Changes))
+
+	change := loaded.Changes[0]
+	assert.False(t, change.Drifted(plannedMarket))
+	assert.Equal(t, "ethusdt", change.Request.Symbol)
+	assert.Equal(t, "0.006", change.Request.MinAmount.String())
+	assert.Equal(t, int64(3), change.Request.AmountPrecision)
+	assert.Equal(t, "9000", change.Request.MaxAmount.String())
+}